@@ -0,0 +1,305 @@
+package main
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+)
+
+// Контрольные точки: состояние симуляции сохраняется в JSON (-k, -K) и
+// продолжается позже (-R), в том числе с другими квантами, лимитами и
+// периодом повышения приоритета. Так нагрузку можно один раз довести до
+// установившегося режима и сравнить несколько политик с одного и того же
+// момента.
+//
+// Случайный I/O восстанавливается точно: в контрольной точке хранится семя
+// и количество выборок генератора каждой задачи.
+
+// countingSource считает выборки генератора, чтобы восстановить его состояние
+type countingSource struct {
+	rand.Source
+	draws uint64
+}
+
+// Int63 возвращает очередное число и учитывает выборку
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.Source.Int63()
+}
+
+// SetRandomIO включает случайный I/O. У каждой задачи свой генератор с
+// семенем seed + ID: длины вычислений и задержки I/O задачи не зависят от
+// того, в каком порядке планировщик выполняет задачи, поэтому разные
+// политики получают на одной нагрузке одинаковый I/O.
+func (m *MLFQ) SetRandomIO(seed int64) {
+	m.RandomIO = true
+	m.randSeed = seed
+}
+
+// jobRand возвращает генератор случайного I/O задачи
+func (m *MLFQ) jobRand(job *Job) *rand.Rand {
+	if job.rand == nil {
+		job.randSource = &countingSource{Source: rand.NewSource(m.randSeed + int64(job.ID))}
+		job.rand = rand.New(job.randSource)
+	}
+	return job.rand
+}
+
+// checkpointJob — задача вместе со служебными полями планировщика
+type checkpointJob struct {
+	*Job
+	QueuedAt   uint   `json:"queued_at"`
+	RanInQueue uint   `json:"ran_in_queue"`
+	ReadySince uint   `json:"ready_since"`
+	IOReady    uint   `json:"io_ready"`
+	IOSeq      uint   `json:"io_seq"`
+	NextBurst  int    `json:"next_burst"`
+	RandDraws  uint64 `json:"rand_draws,omitempty"` // Выборок генератора случайного I/O задачи
+}
+
+// Checkpoint — сохраненное состояние симуляции
+type Checkpoint struct {
+	Time      uint    `json:"time"`
+	LastBoost uint    `json:"last_boost"`
+	Config    *Config `json:"config"`
+
+	CPUs            uint   `json:"cpus"`
+	PerCPU          bool   `json:"per_cpu,omitempty"`
+	Stealing        bool   `json:"stealing,omitempty"`
+	BalanceInterval uint   `json:"balance_interval,omitempty"`
+	CPUBusy         []uint `json:"cpu_busy"`
+	BusyTime        uint   `json:"busy_time"`
+	Migrations      uint   `json:"migrations"`
+
+	RandSeed *int64 `json:"rand_seed,omitempty"` // Семя случайного I/O (nil — I/O не случайный)
+
+	Jobs    []checkpointJob `json:"jobs"`    // Все задачи, включая завершенные и не прибывшие
+	Queues  [][]uint        `json:"queues"`  // ID задач в каждой очереди
+	IO      []uint          `json:"io"`      // Задачи в I/O в порядке начала операций
+	Pending []uint          `json:"pending"` // Задачи, которые еще не прибыли, в порядке прибытия
+	IOSeq   uint            `json:"io_seq"`
+}
+
+// Checkpoint возвращает текущее состояние симуляции. Вызывается между
+// запусками Run.
+func (m *MLFQ) Checkpoint() *Checkpoint {
+	c := &Checkpoint{
+		Time:            m.CurrentTime,
+		LastBoost:       m.LastBoost,
+		Config:          m.Config(),
+		CPUs:            m.CPUs,
+		PerCPU:          m.PerCPU,
+		Stealing:        m.Stealing,
+		BalanceInterval: m.BalanceInterval,
+		CPUBusy:         m.CPUBusy,
+		BusyTime:        m.BusyTime,
+		Migrations:      m.Migrations,
+		IOSeq:           m.ioSeq,
+	}
+	if m.RandomIO {
+		seed := m.randSeed
+		c.RandSeed = &seed
+	}
+
+	save := func(job *Job) {
+		saved := checkpointJob{
+			Job:        job,
+			QueuedAt:   job.queuedAt,
+			RanInQueue: job.ranInQueue,
+			ReadySince: job.readySince,
+			IOReady:    job.ioReady,
+			IOSeq:      job.ioSeq,
+			NextBurst:  job.nextBurst,
+		}
+		if job.randSource != nil {
+			saved.RandDraws = job.randSource.draws
+		}
+		c.Jobs = append(c.Jobs, saved)
+	}
+	for _, job := range m.SortedJobs() {
+		save(job)
+	}
+	m.sortPending()
+	for _, job := range m.PendingJobs {
+		save(job)
+		c.Pending = append(c.Pending, job.ID)
+	}
+	for _, queue := range m.Queues {
+		c.Queues = append(c.Queues, queue.IDs())
+	}
+	for _, job := range m.IOJobs() {
+		c.IO = append(c.IO, job.ID)
+	}
+	return c
+}
+
+// SaveCheckpoint записывает состояние симуляции в файл
+func (m *MLFQ) SaveCheckpoint(path string) error {
+	data, err := json.MarshalIndent(m.Checkpoint(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// LoadCheckpoint читает контрольную точку и восстанавливает по ней планировщик
+func LoadCheckpoint(path string) (*MLFQ, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Checkpoint
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	m, err := c.Restore()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return m, nil
+}
+
+// Restore создает планировщик в сохраненном состоянии
+func (c *Checkpoint) Restore() (*MLFQ, error) {
+	if c.Config == nil {
+		return nil, fmt.Errorf("нет таблицы диспетчеризации")
+	}
+	if err := c.Config.Validate(); err != nil {
+		return nil, err
+	}
+	m := NewMLFQFromConfig(c.Config, c.Config.IODuration)
+	if uint(len(c.Queues)) != m.NumQueues {
+		return nil, fmt.Errorf("очередей %d, уровней в таблице %d", len(c.Queues), m.NumQueues)
+	}
+	m.CurrentTime = c.Time
+	m.LastBoost = c.LastBoost
+	m.PerCPU = c.PerCPU
+	m.Stealing = c.Stealing
+	m.BalanceInterval = c.BalanceInterval
+	m.SetCPUs(c.CPUs)
+	if len(c.CPUBusy) != len(m.CPUBusy) {
+		return nil, fmt.Errorf("загрузка указана для %d CPU из %d", len(c.CPUBusy), m.CPUs)
+	}
+	copy(m.CPUBusy, c.CPUBusy)
+	m.BusyTime = c.BusyTime
+	m.Migrations = c.Migrations
+	m.ioSeq = c.IOSeq
+	if c.RandSeed != nil {
+		m.SetRandomIO(*c.RandSeed)
+	}
+
+	jobs := make(map[uint]*Job)
+	for _, saved := range c.Jobs {
+		job := saved.Job
+		if job == nil || jobs[job.ID] != nil {
+			return nil, fmt.Errorf("задача без ID или с повторяющимся ID")
+		}
+		if len(job.QueueTime) != int(m.NumQueues) && job.QueueTime != nil {
+			return nil, fmt.Errorf("задача %d: время указано для %d уровней из %d", job.ID, len(job.QueueTime), m.NumQueues)
+		}
+		job.queuedAt = saved.QueuedAt
+		job.ranInQueue = saved.RanInQueue
+		job.readySince = saved.ReadySince
+		job.ioReady = saved.IOReady
+		job.ioSeq = saved.IOSeq
+		job.nextBurst = saved.NextBurst
+		if saved.RandDraws > 0 {
+			if !m.RandomIO {
+				return nil, fmt.Errorf("задача %d: выборки случайного I/O без семени", job.ID)
+			}
+			m.jobRand(job)
+			for i := uint64(0); i < saved.RandDraws; i++ {
+				job.randSource.Int63()
+			}
+		}
+		jobs[job.ID] = job
+	}
+
+	// Каждая незавершенная задача должна находиться ровно в одном месте
+	placed := make(map[uint]bool)
+	take := func(id uint) (*Job, error) {
+		job := jobs[id]
+		switch {
+		case job == nil:
+			return nil, fmt.Errorf("неизвестная задача %d", id)
+		case placed[id] || job.Done:
+			return nil, fmt.Errorf("задача %d указана несколько раз", id)
+		}
+		placed[id] = true
+		return job, nil
+	}
+	for level, ids := range c.Queues {
+		for _, id := range ids {
+			job, err := take(id)
+			if err != nil {
+				return nil, err
+			}
+			if job.CurrentQueue != uint(level) {
+				return nil, fmt.Errorf("задача %d в очереди %d, но на уровне %d", id, level, job.CurrentQueue)
+			}
+			m.Jobs[id] = job
+			m.Queues[level].PushBack(job)
+		}
+	}
+	for _, id := range c.IO {
+		job, err := take(id)
+		if err != nil {
+			return nil, err
+		}
+		m.Jobs[id] = job
+		job.doingIO = true
+		heap.Push(&m.ioJobs, job)
+	}
+	for _, id := range c.Pending {
+		job, err := take(id)
+		if err != nil {
+			return nil, err
+		}
+		m.AddPendingJob(job)
+	}
+	for id, job := range jobs {
+		switch {
+		case job.Done:
+			m.Jobs[id] = job
+		case !placed[id]:
+			return nil, fmt.Errorf("задача %d не находится ни в очереди, ни в I/O, ни среди ожидающих", id)
+		}
+	}
+	return m, nil
+}
+
+// SetPolicy задает новые кванты и лимиты квантов уровней (nil — без
+// изменений). Оставшийся квант и лимит задач не превышают новых значений.
+func (m *MLFQ) SetPolicy(timeSlices, allotments []uint) {
+	if timeSlices != nil {
+		m.TimeSlice = timeSlices
+	}
+	if allotments != nil {
+		m.Allotment = allotments
+	}
+	for _, job := range m.Jobs {
+		if job.Done {
+			continue
+		}
+		job.TimeSliceLeft = min(job.TimeSliceLeft, m.TimeSlice[job.CurrentQueue])
+		job.AllotmentLeft = min(job.AllotmentLeft, m.Allotment[job.CurrentQueue])
+	}
+}
+
+// ApplyConfig заменяет таблицу диспетчеризации планировщика таблицей с тем же
+// количеством уровней
+func (m *MLFQ) ApplyConfig(c *Config) error {
+	if uint(len(c.Levels)) != m.NumQueues {
+		return fmt.Errorf("в таблице %d уровней, в контрольной точке %d", len(c.Levels), m.NumQueues)
+	}
+	table := NewMLFQFromConfig(c, m.IODuration)
+	m.DemoteLevel = table.DemoteLevel
+	m.SleepLevel = table.SleepLevel
+	m.BoostTime = table.BoostTime
+	m.IODuration = table.IODuration
+	m.IOMode = table.IOMode
+	m.SetPolicy(table.TimeSlice, table.Allotment)
+	return nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Классы задач случайной нагрузки (-J). Класс задает диапазоны длительности
+// задачи, частоты и длительности I/O, из которых значения выбираются
+// равномерно, и вес — долю задач класса в нагрузке. Время прибытия у всех
+// классов равномерное в [0, -a).
+//
+//	-J interactive:3,batch:1,gamer:1
+//	-J interactive,batch:2:len=100-400
+//
+// Встроенные классы:
+//
+//	interactive  длительность 5–30, I/O каждые 1–3 единицы длительностью 2–8
+//	batch        длительность 50–300, без I/O
+//	gamer        длительность 50–200, I/O на 99% кванта (как -G)
+//
+// После веса можно заменить параметры класса: len=A-B (длительность),
+// io=A-B (частота I/O), iolen=A-B (длительность I/O), gaming. Класс с другим
+// именем описывается полностью, обязательно с len. Статистика выводится и по
+// каждому классу.
+
+// uintRange — диапазон [Min, Max] для равномерного выбора
+type uintRange struct {
+	Min, Max uint
+}
+
+// sample возвращает случайное значение из диапазона
+func (r uintRange) sample(rng *rand.Rand) uint {
+	return r.Min + uint(rng.Intn(int(r.Max-r.Min+1)))
+}
+
+// String возвращает диапазон в формате A-B
+func (r uintRange) String() string {
+	if r.Min == r.Max {
+		return strconv.FormatUint(uint64(r.Min), 10)
+	}
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// parseUintRange разбирает диапазон A-B или одно число A
+func parseUintRange(s string) (uintRange, error) {
+	low, high, ok := strings.Cut(s, "-")
+	if !ok {
+		high = low
+	}
+	lo, err1 := strconv.ParseUint(low, 10, 32)
+	hi, err2 := strconv.ParseUint(high, 10, 32)
+	if err1 != nil || err2 != nil || lo > hi {
+		return uintRange{}, fmt.Errorf("неверный диапазон: %s", s)
+	}
+	return uintRange{uint(lo), uint(hi)}, nil
+}
+
+// JobClass — класс задач случайной нагрузки
+type JobClass struct {
+	Name       string
+	Weight     uint
+	Length     uintRange
+	IOFreq     uintRange // 0 — без I/O
+	IODuration uintRange // 0 — общая длительность I/O (-I)
+	Gaming     bool
+}
+
+// builtinClasses — встроенные классы задач
+var builtinClasses = []JobClass{
+	{Name: "interactive", Length: uintRange{5, 30}, IOFreq: uintRange{1, 3}, IODuration: uintRange{2, 8}},
+	{Name: "batch", Length: uintRange{50, 300}},
+	{Name: "gamer", Length: uintRange{50, 200}, Gaming: true},
+}
+
+// parseClasses разбирает список классов -J
+func parseClasses(spec string) ([]JobClass, error) {
+	var classes []JobClass
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(spec, ",") {
+		fields := strings.Split(strings.TrimSpace(item), ":")
+		class := JobClass{Name: fields[0], Weight: 1}
+		if class.Name == "" {
+			return nil, fmt.Errorf("пустое имя класса")
+		}
+		if seen[class.Name] {
+			return nil, fmt.Errorf("класс %s указан несколько раз", class.Name)
+		}
+		seen[class.Name] = true
+		for _, builtin := range builtinClasses {
+			if builtin.Name == class.Name {
+				class = builtin
+				class.Weight = 1
+			}
+		}
+
+		params := fields[1:]
+		if len(params) > 0 && !strings.Contains(params[0], "=") && params[0] != "gaming" {
+			weight, err := strconv.ParseUint(params[0], 10, 32)
+			if err != nil || weight == 0 {
+				return nil, fmt.Errorf("класс %s: неверный вес: %s", class.Name, params[0])
+			}
+			class.Weight = uint(weight)
+			params = params[1:]
+		}
+		for _, param := range params {
+			if param == "gaming" {
+				class.Gaming = true
+				continue
+			}
+			key, value, _ := strings.Cut(param, "=")
+			r, err := parseUintRange(value)
+			if err != nil {
+				return nil, fmt.Errorf("класс %s: %v", class.Name, err)
+			}
+			switch key {
+			case "len":
+				class.Length = r
+			case "io":
+				class.IOFreq = r
+			case "iolen":
+				class.IODuration = r
+			default:
+				return nil, fmt.Errorf("класс %s: неизвестный параметр %s (доступны: len, io, iolen, gaming)", class.Name, key)
+			}
+		}
+		if class.Length.Min == 0 {
+			return nil, fmt.Errorf("класс %s: нужна длительность len=A-B больше 0", class.Name)
+		}
+		if class.IOFreq.Min == 0 && class.IOFreq.Max > 0 {
+			return nil, fmt.Errorf("класс %s: частота I/O должна быть больше 0", class.Name)
+		}
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
+// classJobs генерирует numJobs задач. Количество задач классов
+// пропорционально весам (остаток деления достается классам с наибольшей
+// дробной частью), порядок классов случайный.
+func classJobs(rng *rand.Rand, classes []JobClass, numJobs, maxArrival uint) []*Job {
+	var total uint
+	for _, class := range classes {
+		total += class.Weight
+	}
+	counts := make([]uint, len(classes))
+	order := make([]int, len(classes))
+	var assigned uint
+	for i, class := range classes {
+		counts[i] = numJobs * class.Weight / total
+		assigned += counts[i]
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return numJobs*classes[order[a]].Weight%total > numJobs*classes[order[b]].Weight%total
+	})
+	for i := uint(0); assigned < numJobs; i++ {
+		counts[order[i]]++
+		assigned++
+	}
+
+	var mix []*JobClass
+	for i := range classes {
+		for n := uint(0); n < counts[i]; n++ {
+			mix = append(mix, &classes[i])
+		}
+	}
+	rng.Shuffle(len(mix), func(i, j int) { mix[i], mix[j] = mix[j], mix[i] })
+
+	jobs := make([]*Job, len(mix))
+	for i, class := range mix {
+		jobs[i] = &Job{
+			ID:          uint(i + 1),
+			Class:       class.Name,
+			ArrivalTime: uint(rng.Intn(int(maxArrival))),
+			JobLength:   class.Length.sample(rng),
+			IOFrequency: class.IOFreq.sample(rng),
+			IODuration:  class.IODuration.sample(rng),
+			Gaming:      class.Gaming,
+			StartTime:   -1,
+		}
+	}
+	return jobs
+}
+
+// printClassStats выводит статистику по классам задач
+func (m *MLFQ) printClassStats() {
+	type classStats struct {
+		jobs, done                 int
+		response, turnaround, wait float64
+		maxWait, cpu               uint
+	}
+	stats := make(map[string]*classStats)
+	var names []string
+	// Задачи, которые не прибыли, тоже учитываются в количестве задач класса
+	for _, job := range append(m.SortedJobs(), m.PendingJobs...) {
+		if job.Class == "" {
+			continue
+		}
+		s := stats[job.Class]
+		if s == nil {
+			s = &classStats{}
+			stats[job.Class] = s
+			names = append(names, job.Class)
+		}
+		s.jobs++
+		s.cpu += job.JobLength - job.TimeLeft
+		s.maxWait = max(s.maxWait, job.MaxWaitStreak)
+		if job.Done {
+			s.done++
+			s.response += float64(job.StartTime - int(job.ArrivalTime))
+			s.turnaround += float64(job.EndTime - job.ArrivalTime)
+			s.wait += float64(job.TotalWait)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+
+	fmt.Println("\n=== Классы задач ===")
+	fmt.Printf("%-12s %-10s %-8s %-10s %-9s %-14s %s\n",
+		"Класс", "Завершено", "Отклик", "Оборотное", "Ожидание", "Макс. без CPU", "Доля CPU")
+	for _, name := range names {
+		s := stats[name]
+		n := float64(max(s.done, 1))
+		share := 0.0
+		if m.BusyTime > 0 {
+			share = 100 * float64(s.cpu) / float64(m.BusyTime)
+		}
+		fmt.Printf("%-12s %-10s %-8.2f %-10.2f %-9.2f %-14d %.1f%%\n",
+			name, fmt.Sprintf("%d/%d", s.done, s.jobs), s.response/n, s.turnaround/n, s.wait/n, s.maxWait, share)
+	}
+}
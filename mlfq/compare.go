@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Сравнение MLFQ с классическими политиками (-p) на той же рабочей нагрузке.
+//
+// Политики выполняются тем же движком, поэтому I/O и метрики у них считаются
+// так же, как у MLFQ. FIFO — одна очередь с квантом, который никогда не
+// истекает; RR — одна очередь с квантом N; SJF — одна очередь, из которой
+// выбирается задача с наименьшим оставшимся временем, и начатая задача
+// выполняется до завершения или I/O. После I/O задача встает в конец очереди.
+// Подсказки уровней задач (queue, ceil, floor) политиками не используются.
+
+// comparePolicy — политика для сравнения
+type comparePolicy struct {
+	Name     string
+	Quantum  uint // Квант RR (0 — квант не истекает)
+	Shortest bool // SJF
+}
+
+// parsePolicies разбирает список политик: fifo, sjf, rr (квант rrQuantum) или rr:N
+func parsePolicies(list string, rrQuantum uint) ([]comparePolicy, error) {
+	var policies []comparePolicy
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case name == "fifo":
+			policies = append(policies, comparePolicy{Name: "FIFO"})
+		case name == "sjf":
+			policies = append(policies, comparePolicy{Name: "SJF", Shortest: true})
+		case name == "rr" || strings.HasPrefix(name, "rr:"):
+			quantum := rrQuantum
+			if value, ok := strings.CutPrefix(name, "rr:"); ok {
+				q, err := strconv.ParseUint(value, 10, 32)
+				if err != nil || q == 0 {
+					return nil, fmt.Errorf("неверный квант RR: %s", value)
+				}
+				quantum = uint(q)
+			}
+			policies = append(policies, comparePolicy{Name: fmt.Sprintf("RR(%d)", quantum), Quantum: quantum})
+		default:
+			return nil, fmt.Errorf("неизвестная политика: %s (доступны: fifo, sjf, rr, rr:N)", name)
+		}
+	}
+	return policies, nil
+}
+
+// scheduler создает планировщик с одной очередью, работающий по политике
+func (p comparePolicy) scheduler(ioDuration uint) *MLFQ {
+	quantum := p.Quantum
+	if quantum == 0 {
+		quantum = math.MaxUint32
+	}
+	m := NewMLFQ(1, []uint{quantum}, nil, 0, ioDuration)
+	m.ShortestFirst = p.Shortest
+	return m
+}
+
+// shortestJob возвращает задачу очереди с наименьшим оставшимся временем
+// (при равенстве — первую по порядку очереди). Задача, выполнявшаяся на
+// предыдущем такте, не вытесняется.
+func (m *MLFQ) shortestJob(queue *JobQueue) *Job {
+	var best *Job
+	for job := queue.Front(); job != nil; job = job.next {
+		if job.StartTime >= 0 && job.LastRun+1 == m.CurrentTime {
+			return job
+		}
+		if best == nil || job.TimeLeft < best.TimeLeft {
+			best = job
+		}
+	}
+	return best
+}
+
+// compareRow — итог одной политики
+type compareRow struct {
+	Name       string
+	Response   float64 // Средние значения по завершенным задачам
+	Turnaround float64
+	Wait       float64
+	MaxWait    uint // Наибольший перерыв задачи без CPU
+	Finished   int
+	Total      int
+	Time       uint // Момент окончания симуляции
+}
+
+// summarize собирает итог симуляции
+func summarize(name string, m *MLFQ) compareRow {
+	row := compareRow{Name: name, Total: len(m.Jobs) + len(m.PendingJobs), Time: m.CurrentTime}
+	for _, job := range m.SortedJobs() {
+		row.MaxWait = max(row.MaxWait, job.MaxWaitStreak)
+		if !job.Done {
+			continue
+		}
+		row.Finished++
+		row.Response += float64(job.StartTime - int(job.ArrivalTime))
+		row.Turnaround += float64(job.EndTime - job.ArrivalTime)
+		row.Wait += float64(job.TotalWait)
+	}
+	if row.Finished > 0 {
+		n := float64(row.Finished)
+		row.Response /= n
+		row.Turnaround /= n
+		row.Wait /= n
+	}
+	return row
+}
+
+// comparePolicies выполняет нагрузку по каждой политике с теми же
+// длительностью I/O, случайным I/O и ограничением времени, что и у MLFQ
+func comparePolicies(mlfq *MLFQ, jobs []*Job, policies []comparePolicy, maxTime uint) []compareRow {
+	rows := []compareRow{summarize("MLFQ", mlfq)}
+	for _, p := range policies {
+		m := p.scheduler(mlfq.IODuration)
+		if mlfq.RandomIO {
+			m.SetRandomIO(mlfq.randSeed)
+		}
+		for _, job := range cloneJobs(jobs) {
+			job.InitialQueue, job.Ceiling, job.Floor = 0, 0, nil
+			m.AddPendingJob(job)
+		}
+		m.Run(maxTime)
+		rows = append(rows, summarize(p.Name, m))
+	}
+	return rows
+}
+
+// printComparison выводит итоги политик одной таблицей
+func printComparison(rows []compareRow) {
+	fmt.Println("\n=== Сравнение политик ===")
+	fmt.Printf("%-8s %-8s %-10s %-9s %-14s %-10s %s\n",
+		"Политика", "Отклик", "Оборотное", "Ожидание", "Макс. без CPU", "Завершено", "Время")
+	for _, row := range rows {
+		fmt.Printf("%-8s %-8.2f %-10.2f %-9.2f %-14d %-10s %d\n",
+			row.Name, row.Response, row.Turnaround, row.Wait, row.MaxWait,
+			fmt.Sprintf("%d/%d", row.Finished, row.Total), row.Time)
+	}
+}
@@ -0,0 +1,456 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LevelConfig — строка таблицы диспетчеризации (как ts_dptbl в Solaris TS).
+// Уровень 0 — наивысший приоритет.
+type LevelConfig struct {
+	Quantum   uint  `json:"quantum"`          // Временной квант уровня
+	Allotment uint  `json:"allotment"`        // Квантов на уровне до понижения (0 = 1)
+	Demote    *uint `json:"demote,omitempty"` // Уровень после исчерпания лимита (ts_tqexp); по умолчанию следующий
+	Sleep     *uint `json:"sleep,omitempty"`  // Уровень после I/O в режиме bump (ts_slpret); по умолчанию 0
+}
+
+// Config — полная конфигурация планировщика из файла
+type Config struct {
+	Levels     []LevelConfig `json:"levels"`
+	Boost      uint          `json:"boost"`                 // Период повышения приоритета (0 = отключено)
+	IODuration uint          `json:"io_duration,omitempty"` // Длительность I/O (0 = значение по умолчанию)
+	IOMode     IOMode        `json:"io_mode,omitempty"`     // bump, keep или account
+}
+
+// LoadConfig читает таблицу диспетчеризации из JSON или YAML файла.
+// Формат определяется по расширению: .yaml и .yml — YAML, иначе JSON.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		value, err := parseYAML(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		// Приводим YAML к JSON, чтобы использовать одни и те же теги полей
+		if data, err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+
+	var cfg Config
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate проверяет таблицу и заполняет значения по умолчанию
+func (c *Config) Validate() error {
+	n := uint(len(c.Levels))
+	if n == 0 {
+		return fmt.Errorf("таблица не содержит уровней")
+	}
+	if c.IOMode == "" {
+		c.IOMode = IOModeBump
+	} else if _, err := parseIOMode(string(c.IOMode)); err != nil {
+		return err
+	}
+
+	for i := range c.Levels {
+		level := &c.Levels[i]
+		if level.Quantum == 0 {
+			return fmt.Errorf("уровень %d: квант должен быть больше 0", i)
+		}
+		if level.Allotment == 0 {
+			level.Allotment = 1
+		}
+		if level.Demote == nil {
+			demote := min(uint(i)+1, n-1)
+			level.Demote = &demote
+		}
+		if level.Sleep == nil {
+			sleep := uint(0)
+			level.Sleep = &sleep
+		}
+		if *level.Demote >= n {
+			return fmt.Errorf("уровень %d: нет уровня понижения %d", i, *level.Demote)
+		}
+		if *level.Sleep >= n {
+			return fmt.Errorf("уровень %d: нет уровня возврата после I/O %d", i, *level.Sleep)
+		}
+	}
+	return nil
+}
+
+// NewMLFQFromConfig создает планировщик по таблице диспетчеризации.
+// defaultIODuration используется, если длительность I/O в таблице не задана.
+func NewMLFQFromConfig(c *Config, defaultIODuration uint) *MLFQ {
+	n := uint(len(c.Levels))
+	quanta := make([]uint, n)
+	allotments := make([]uint, n)
+	demote := make([]uint, n)
+	sleep := make([]uint, n)
+	for i, level := range c.Levels {
+		quanta[i] = level.Quantum
+		allotments[i] = level.Allotment
+		demote[i] = *level.Demote
+		sleep[i] = *level.Sleep
+	}
+
+	ioDuration := c.IODuration
+	if ioDuration == 0 {
+		ioDuration = defaultIODuration
+	}
+
+	m := NewMLFQ(n, quanta, allotments, c.Boost, ioDuration)
+	m.DemoteLevel = demote
+	m.SleepLevel = sleep
+	m.IOMode = c.IOMode
+	return m
+}
+
+// Config возвращает текущую конфигурацию планировщика в виде таблицы
+func (m *MLFQ) Config() *Config {
+	c := &Config{Boost: m.BoostTime, IODuration: m.IODuration, IOMode: m.IOMode}
+	for i := uint(0); i < m.NumQueues; i++ {
+		demote, sleep := m.lowerLevel(i), m.sleepLevel(i)
+		c.Levels = append(c.Levels, LevelConfig{
+			Quantum:   m.TimeSlice[i],
+			Allotment: m.Allotment[i],
+			Demote:    &demote,
+			Sleep:     &sleep,
+		})
+	}
+	return c
+}
+
+// printDispatchTable выводит таблицу диспетчеризации
+func (m *MLFQ) printDispatchTable() {
+	fmt.Printf("%-8s %-6s %-8s %-10s %s\n", "Уровень", "Квант", "Квантов", "Понижение", "После I/O")
+	for i := uint(0); i < m.NumQueues; i++ {
+		fmt.Printf("%-8d %-6d %-8d %-10d %d\n", i, m.TimeSlice[i], m.Allotment[i], m.lowerLevel(i), m.sleepLevel(i))
+	}
+}
+
+// yamlLine — значимая строка YAML без комментария
+type yamlLine struct {
+	num    int // Номер строки в файле
+	indent int
+	text   string
+}
+
+// parseYAML разбирает подмножество YAML, достаточное для таблиц
+// диспетчеризации: вложенные словари, списки "- ", однострочные списки в
+// квадратных скобках и словари в фигурных, числа, true/false и строки (в том
+// числе в кавычках). Результат совместим с encoding/json.
+func parseYAML(src string) (any, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(src, "\n") {
+		if strings.Contains(raw, "\t") {
+			return nil, fmt.Errorf("строка %d: табуляция в отступе не поддерживается", i+1)
+		}
+		text := stripYAMLComment(raw)
+		if strings.TrimSpace(text) == "" || strings.TrimSpace(text) == "---" {
+			continue
+		}
+		trimmed := strings.TrimLeft(text, " ")
+		lines = append(lines, yamlLine{num: i + 1, indent: len(text) - len(trimmed), text: strings.TrimRight(trimmed, " ")})
+	}
+	if len(lines) == 0 {
+		return map[string]any{}, nil
+	}
+
+	p := &yamlParser{lines: lines}
+	value, err := p.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("строка %d: неверный отступ", p.lines[p.pos].num)
+	}
+	return value, nil
+}
+
+// stripYAMLComment удаляет комментарий "#" вне кавычек
+func stripYAMLComment(s string) string {
+	quote := byte(0)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// block разбирает словарь или список, строки которого имеют отступ indent
+func (p *yamlParser) block(indent int) (any, error) {
+	if strings.HasPrefix(p.lines[p.pos].text, "- ") || p.lines[p.pos].text == "-" {
+		return p.list(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) list(indent int) (any, error) {
+	items := []any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		if !strings.HasPrefix(line.text, "- ") && line.text != "-" {
+			// Список с отступом ключа закончился, дальше следующий ключ
+			break
+		}
+		rest := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		if rest == "" {
+			// Вложенный блок на следующих строках
+			p.pos++
+			if p.pos >= len(p.lines) || p.lines[p.pos].indent <= indent {
+				items = append(items, nil)
+				continue
+			}
+			value, err := p.block(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+			continue
+		}
+		if _, _, ok := splitYAMLKey(rest); ok {
+			// "- key: value": словарь, первая строка которого начинается после "- "
+			p.lines[p.pos] = yamlLine{num: line.num, indent: indent + len(line.text) - len(rest), text: rest}
+			value, err := p.mapping(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+			continue
+		}
+		value, err := parseYAMLScalar(rest, line.num)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+		p.pos++
+	}
+	return items, nil
+}
+
+func (p *yamlParser) mapping(indent int) (any, error) {
+	result := map[string]any{}
+	for p.pos < len(p.lines) && p.lines[p.pos].indent == indent {
+		line := p.lines[p.pos]
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("строка %d: ожидается \"ключ: значение\"", line.num)
+		}
+		if _, dup := result[key]; dup {
+			return nil, fmt.Errorf("строка %d: повторный ключ %q", line.num, key)
+		}
+		p.pos++
+
+		if rest != "" {
+			value, err := parseYAMLScalar(rest, line.num)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = value
+			continue
+		}
+
+		// Значение — вложенный блок. Список может иметь тот же отступ, что и ключ.
+		if p.pos < len(p.lines) && (p.lines[p.pos].indent > indent ||
+			(p.lines[p.pos].indent == indent && strings.HasPrefix(p.lines[p.pos].text, "-"))) {
+			value, err := p.block(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = value
+		} else {
+			result[key] = nil
+		}
+	}
+	return result, nil
+}
+
+// splitYAMLKey разделяет строку "ключ: значение"
+func splitYAMLKey(s string) (key, rest string, ok bool) {
+	if strings.HasPrefix(s, "\"") || strings.HasPrefix(s, "'") || strings.HasPrefix(s, "[") || strings.HasPrefix(s, "{") {
+		return "", "", false
+	}
+	i := strings.Index(s, ": ")
+	if i < 0 {
+		if !strings.HasSuffix(s, ":") {
+			return "", "", false
+		}
+		i = len(s) - 1
+	}
+	key = strings.TrimSpace(s[:i])
+	if key == "" {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(s[i+1:]), true
+}
+
+// parseYAMLScalar разбирает скаляр, список в квадратных скобках или словарь
+// в фигурных
+func parseYAMLScalar(s string, num int) (any, error) {
+	if strings.HasPrefix(s, "[") {
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("строка %d: незакрытый список", num)
+		}
+		parts, err := splitYAMLFlow(s[1:len(s)-1], num)
+		if err != nil {
+			return nil, err
+		}
+		items := []any{}
+		for _, part := range parts {
+			value, err := parseYAMLScalar(part, num)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	}
+
+	if strings.HasPrefix(s, "{") {
+		if !strings.HasSuffix(s, "}") {
+			return nil, fmt.Errorf("строка %d: незакрытый словарь", num)
+		}
+		parts, err := splitYAMLFlow(s[1:len(s)-1], num)
+		if err != nil {
+			return nil, err
+		}
+		result := map[string]any{}
+		for _, part := range parts {
+			key, rest, ok := splitYAMLFlowKey(part)
+			if !ok {
+				return nil, fmt.Errorf("строка %d: ожидается \"ключ: значение\" в словаре: %s", num, part)
+			}
+			if _, dup := result[key]; dup {
+				return nil, fmt.Errorf("строка %d: повторный ключ %q", num, key)
+			}
+			if rest == "" {
+				result[key] = nil
+				continue
+			}
+			value, err := parseYAMLScalar(rest, num)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = value
+		}
+		return result, nil
+	}
+	if strings.HasPrefix(s, "]") || strings.HasPrefix(s, "}") {
+		return nil, fmt.Errorf("строка %d: лишняя скобка: %s", num, s)
+	}
+
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') {
+		if s[len(s)-1] != s[0] {
+			return nil, fmt.Errorf("строка %d: незакрытая строка", num)
+		}
+		return s[1 : len(s)-1], nil
+	}
+
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null", "~":
+		return nil, nil
+	}
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return v, nil
+	}
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v, nil
+	}
+	return s, nil
+}
+
+// splitYAMLFlow разделяет содержимое скобок по запятым верхнего уровня
+func splitYAMLFlow(inner string, num int) ([]string, error) {
+	var parts []string
+	depth, start := 0, 0
+	quote := byte(0)
+	for i := 0; i < len(inner); i++ {
+		switch c := inner[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			if depth--; depth < 0 {
+				return nil, fmt.Errorf("строка %d: лишняя скобка", num)
+			}
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(inner[start:i]))
+			start = i + 1
+		}
+	}
+	if depth != 0 || quote != 0 {
+		return nil, fmt.Errorf("строка %d: незакрытая скобка или строка", num)
+	}
+	last := strings.TrimSpace(inner[start:])
+	if last == "" && len(parts) == 0 {
+		return nil, nil
+	}
+	parts = append(parts, last)
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("строка %d: пустой элемент", num)
+		}
+	}
+	return parts, nil
+}
+
+// splitYAMLFlowKey разделяет элемент словаря в фигурных скобках; ключ может
+// быть в кавычках
+func splitYAMLFlowKey(s string) (key, rest string, ok bool) {
+	if len(s) > 0 && (s[0] == '"' || s[0] == '\'') {
+		end := strings.IndexByte(s[1:], s[0])
+		if end < 0 {
+			return "", "", false
+		}
+		key, rest = s[1:end+1], strings.TrimSpace(s[end+2:])
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(rest[1:]), true
+	}
+	i := strings.IndexByte(s, ':')
+	if i <= 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:]), true
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		want any
+	}{
+		{
+			name: "пустой документ",
+			src:  "# только комментарий\n---\n",
+			want: map[string]any{},
+		},
+		{
+			name: "скаляры",
+			src:  "a: 1\nb: -2.5\nc: true\nd: false\ne: null\nf: ~\ng: bump\nh: \"a # b\"  # комментарий\ni: 'x'",
+			want: map[string]any{
+				"a": int64(1), "b": -2.5, "c": true, "d": false, "e": nil, "f": nil,
+				"g": "bump", "h": "a # b", "i": "x",
+			},
+		},
+		{
+			name: "список с отступом",
+			src:  "levels:\n  - quantum: 4\n    allotment: 2\n  - quantum: 8\nboost: 100",
+			want: map[string]any{
+				"levels": []any{
+					map[string]any{"quantum": int64(4), "allotment": int64(2)},
+					map[string]any{"quantum": int64(8)},
+				},
+				"boost": int64(100),
+			},
+		},
+		{
+			name: "список с отступом ключа и следующий ключ",
+			src:  "levels:\n- quantum: 5\n  demote: 0\nboost: 10",
+			want: map[string]any{
+				"levels": []any{map[string]any{"quantum": int64(5), "demote": int64(0)}},
+				"boost":  int64(10),
+			},
+		},
+		{
+			name: "список скаляров с отступом ключа",
+			src:  "a:\n- 1\n- 2\nb:\n- x\n",
+			want: map[string]any{"a": []any{int64(1), int64(2)}, "b": []any{"x"}},
+		},
+		{
+			name: "вложенные словари",
+			src:  "a:\n  b:\n    c: 1\n  d: 2\ne:",
+			want: map[string]any{"a": map[string]any{"b": map[string]any{"c": int64(1)}, "d": int64(2)}, "e": nil},
+		},
+		{
+			name: "вложенный блок после -",
+			src:  "-\n  quantum: 5\n- 7\n-",
+			want: []any{map[string]any{"quantum": int64(5)}, int64(7), nil},
+		},
+		{
+			name: "список в скобках",
+			src:  "quanta: [4, 8, 16]\nempty: []",
+			want: map[string]any{"quanta": []any{int64(4), int64(8), int64(16)}, "empty": []any{}},
+		},
+		{
+			name: "словари в фигурных скобках",
+			src:  "levels:\n  - {quantum: 5, allotment: 2}\n  - {\"quantum\": 10, demote: 0, sleep: }",
+			want: map[string]any{"levels": []any{
+				map[string]any{"quantum": int64(5), "allotment": int64(2)},
+				map[string]any{"quantum": int64(10), "demote": int64(0), "sleep": nil},
+			}},
+		},
+		{
+			name: "вложенные скобки",
+			src:  "levels: [{quantum: 5}, {quantum: 10, tags: [a, \"b, c\"]}]",
+			want: map[string]any{"levels": []any{
+				map[string]any{"quantum": int64(5)},
+				map[string]any{"quantum": int64(10), "tags": []any{"a", "b, c"}},
+			}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := parseYAML(c.src)
+			if err != nil {
+				t.Fatalf("ошибка: %v", err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("получено %#v, ожидается %#v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		err  string // Подстрока ожидаемой ошибки
+	}{
+		{"табуляция", "levels:\n\t- 1", "строка 2: табуляция"},
+		{"повторный ключ", "boost: 1\nboost: 2", "строка 2: повторный ключ \"boost\""},
+		{"строка без ключа", "boost: 1\nquantum", "строка 2: ожидается \"ключ: значение\""},
+		{"неверный отступ", "boost: 1\n  quantum: 2", "строка 2: неверный отступ"},
+		{"ключ после списка верхнего уровня", "- 1\nboost: 2", "строка 2: неверный отступ"},
+		{"незакрытый список", "quanta: [1, 2", "строка 1: незакрытый список"},
+		{"незакрытый словарь", "level: {quantum: 5", "строка 1: незакрытый словарь"},
+		{"незакрытая строка", "mode: \"bump", "строка 1: незакрытая строка"},
+		{"незакрытая скобка внутри", "levels: [{quantum: 5]", "строка 1: незакрытая скобка"},
+		{"лишняя скобка", "quanta: [1], 2]", "строка 1: лишняя скобка"},
+		{"пустой элемент", "quanta: [1, , 2]", "строка 1: пустой элемент"},
+		{"элемент словаря без ключа", "- {quantum}", "строка 1: ожидается \"ключ: значение\" в словаре"},
+		{"повторный ключ в скобках", "level: {quantum: 1, quantum: 2}", "строка 1: повторный ключ \"quantum\""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := parseYAML(c.src)
+			if err == nil {
+				t.Fatalf("ожидается ошибка %q", c.err)
+			}
+			if !strings.Contains(err.Error(), c.err) {
+				t.Errorf("ошибка %q, ожидается %q", err, c.err)
+			}
+		})
+	}
+}
+
+// TestLoadConfigYAMLMatchesJSON проверяет, что одна таблица в YAML и JSON
+// дает одинаковую конфигурацию
+func TestLoadConfigYAMLMatchesJSON(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"ts.yaml": "boost: 10\nio_mode: keep\nlevels:\n- quantum: 5\n  demote: 0\n- {quantum: 10, allotment: 2}\n",
+		"ts.json": `{"boost": 10, "io_mode": "keep", "levels": [{"quantum": 5, "demote": 0}, {"quantum": 10, "allotment": 2}]}`,
+	}
+	configs := map[string]*Config{}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		configs[name] = cfg
+	}
+	if !reflect.DeepEqual(configs["ts.yaml"], configs["ts.json"]) {
+		t.Errorf("YAML %+v, JSON %+v", configs["ts.yaml"], configs["ts.json"])
+	}
+	if got := configs["ts.yaml"]; got.Boost != 10 || len(got.Levels) != 2 || *got.Levels[0].Demote != 0 || got.Levels[1].Allotment != 2 {
+		t.Errorf("неверная конфигурация: %+v", got)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// EventType — тип решения или события планировщика
+type EventType int
+
+const (
+	EventArrival    EventType = iota // Задача прибыла и поставлена в очередь
+	EventDispatch                    // Задача получила процессор на Duration единиц времени
+	EventIdle                        // Процессор простаивает
+	EventDemote                      // Приоритет задачи понижен
+	EventBoost                       // Приоритет всех задач повышен
+	EventIOStart                     // Задача начала I/O
+	EventIOComplete                  // Задача завершила I/O и вернулась в очередь
+	EventFinish                      // Задача завершена
+	EventMigrate                     // Задача перенесена в очередь другого CPU
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventArrival:
+		return "ARRIVAL"
+	case EventDispatch:
+		return "DISPATCH"
+	case EventIdle:
+		return "IDLE"
+	case EventDemote:
+		return "DEMOTE"
+	case EventBoost:
+		return "BOOST"
+	case EventIOStart:
+		return "IO_START"
+	case EventIOComplete:
+		return "IO_DONE"
+	case EventFinish:
+		return "FINISH"
+	case EventMigrate:
+		return "MIGRATE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Event описывает одно событие планировщика
+type Event struct {
+	Type  EventType
+	Time  uint // Момент события
+	JobID uint // 0 для IDLE и BOOST
+	From  uint // Уровень задачи до события (DEMOTE, IO_DONE) или CPU (MIGRATE)
+	To    uint // Уровень задачи после события (ARRIVAL, DISPATCH, DEMOTE, IO_DONE) или CPU (MIGRATE)
+	CPU   uint // Процессор (DISPATCH, IDLE)
+
+	// Duration — сколько единиц подряд, начиная с Time, задача выполняется или
+	// процессор простаивает (DISPATCH, IDLE); состояние очередей все это время
+	// одно и то же
+	Duration uint
+}
+
+// Observer получает события планировщика. Обработчик вызывается синхронно,
+// поэтому состояние m соответствует моменту события.
+type Observer interface {
+	OnEvent(m *MLFQ, e Event)
+}
+
+// ObserverFunc позволяет использовать функцию как Observer
+type ObserverFunc func(m *MLFQ, e Event)
+
+// OnEvent вызывает f(m, e)
+func (f ObserverFunc) OnEvent(m *MLFQ, e Event) {
+	f(m, e)
+}
+
+// AddObserver подписывает наблюдателя на события планировщика
+func (m *MLFQ) AddObserver(o Observer) {
+	m.Observers = append(m.Observers, o)
+}
+
+// emit рассылает событие всем наблюдателям
+func (m *MLFQ) emit(e Event) {
+	for _, o := range m.Observers {
+		o.OnEvent(m, e)
+	}
+}
+
+// TextPrinter выводит ход симуляции в виде таблицы: по строке на каждую единицу
+// времени и сообщения о завершении задач. В режиме Verbose выводятся также
+// прибытия, понижения, повышения приоритета и I/O.
+type TextPrinter struct {
+	w       io.Writer
+	Verbose bool
+	started bool
+}
+
+// NewTextPrinter создает TextPrinter, пишущий в w
+func NewTextPrinter(w io.Writer) *TextPrinter {
+	return &TextPrinter{w: w}
+}
+
+// OnEvent выводит событие
+func (p *TextPrinter) OnEvent(m *MLFQ, e Event) {
+	if !p.started {
+		p.started = true
+		if m.CPUs > 1 {
+			fmt.Fprintf(p.w, "Время: %4s | CPU | Выполняется: %4s | Очереди: %s\n", "T", "Job", "Q0:Q1:Q2")
+		} else {
+			fmt.Fprintf(p.w, "Время: %4s | Выполняется: %4s | Очереди: %s\n", "T", "Job", "Q0:Q1:Q2")
+		}
+		fmt.Fprintln(p.w, "------------------------------------------------------------")
+	}
+
+	// При нескольких CPU в строке указывается процессор
+	cpu := ""
+	if m.CPUs > 1 {
+		cpu = fmt.Sprintf(" %3d |", e.CPU)
+	}
+	switch e.Type {
+	case EventDispatch, EventIdle:
+		job := "IDLE"
+		if e.Type == EventDispatch {
+			job = strconv.FormatUint(uint64(e.JobID), 10)
+		}
+		status := m.getQueueStatus()
+		for t := e.Time; t < e.Time+max(e.Duration, 1); t++ {
+			fmt.Fprintf(p.w, "%4d |%s %8s | %s\n", t, cpu, job, status)
+		}
+	case EventFinish:
+		fmt.Fprintf(p.w, "Задача %s завершена в время %d\n", m.Jobs[e.JobID].Label(), e.Time)
+	}
+
+	if !p.Verbose {
+		return
+	}
+	switch e.Type {
+	case EventArrival:
+		fmt.Fprintf(p.w, "[%d] Задача %d прибыла в очередь %d\n", e.Time, e.JobID, e.To)
+	case EventDemote:
+		fmt.Fprintf(p.w, "[%d] Задача %d понижена: очередь %d -> %d\n", e.Time, e.JobID, e.From, e.To)
+	case EventBoost:
+		fmt.Fprintf(p.w, "[%d] Повышение приоритета всех задач\n", e.Time)
+	case EventIOStart:
+		fmt.Fprintf(p.w, "[%d] Задача %d начала I/O\n", e.Time, e.JobID)
+	case EventIOComplete:
+		fmt.Fprintf(p.w, "[%d] Задача %d завершила I/O: очередь %d -> %d\n", e.Time, e.JobID, e.From, e.To)
+	case EventMigrate:
+		fmt.Fprintf(p.w, "[%d] Задача %d перенесена: CPU %d -> %d\n", e.Time, e.JobID, e.From, e.To)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Подсказки задаются в -w после позиционных полей в виде ключ=значение:
+//
+//	queue=N  начальный уровень задачи (по умолчанию 0, ограничивается ceil и floor)
+//	ceil=N   самый высокий уровень, на который задача может подняться
+//	floor=N  самый низкий уровень, на который задача может опуститься
+//
+// Например, "0,100,,,queue=2" — пакетная задача, начинающая с уровня 2,
+// "0,50,5,,floor=0" — демон, который никогда не покидает уровень 0.
+
+// parseHint разбирает подсказку "ключ=значение" и записывает ее в задачу
+func parseHint(job *Job, hint string) error {
+	key, value, ok := strings.Cut(hint, "=")
+	if !ok {
+		return fmt.Errorf("неверная подсказка: %s", hint)
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+	if err != nil {
+		return fmt.Errorf("неверное значение подсказки: %s", hint)
+	}
+	level := uint(v)
+
+	switch strings.TrimSpace(key) {
+	case "queue":
+		job.InitialQueue = level
+	case "ceil":
+		job.Ceiling = level
+	case "floor":
+		job.Floor = &level
+	default:
+		return fmt.Errorf("неизвестная подсказка: %s (доступны: queue, ceil, floor)", key)
+	}
+	return nil
+}
+
+// validateHints проверяет подсказки задачи для планировщика с numQueues уровнями
+func validateHints(job *Job, numQueues uint) error {
+	floor := numQueues - 1
+	if job.Floor != nil {
+		floor = *job.Floor
+	}
+	switch {
+	case floor >= numQueues:
+		return fmt.Errorf("задача %d: нет уровня %d", job.ID, floor)
+	case job.Ceiling > floor:
+		return fmt.Errorf("задача %d: ceil=%d ниже floor=%d", job.ID, job.Ceiling, floor)
+	case job.InitialQueue >= numQueues:
+		return fmt.Errorf("задача %d: нет уровня %d", job.ID, job.InitialQueue)
+	}
+	return nil
+}
+
+// clampLevel ограничивает уровень задачи ее подсказками ceil и floor
+func (m *MLFQ) clampLevel(job *Job, level uint) uint {
+	if level < job.Ceiling {
+		level = job.Ceiling
+	}
+	if job.Floor != nil && level > *job.Floor {
+		level = *job.Floor
+	}
+	return min(level, m.NumQueues-1)
+}
+
+// hintString возвращает подсказки задачи для вывода, например " queue=2 floor=2"
+func hintString(job *Job) string {
+	var s string
+	if job.InitialQueue > 0 {
+		s += fmt.Sprintf(" queue=%d", job.InitialQueue)
+	}
+	if job.Ceiling > 0 {
+		s += fmt.Sprintf(" ceil=%d", job.Ceiling)
+	}
+	if job.Floor != nil {
+		s += fmt.Sprintf(" floor=%d", *job.Floor)
+	}
+	return s
+}
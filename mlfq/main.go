@@ -1,20 +1,55 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"math"
 	"math/rand"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// IOMode определяет, как учитывается процессорное время задачи, выполняющей I/O
+type IOMode string
+
+const (
+	// IOModeBump: после I/O задача возвращается в очередь 0 с новым квантом
+	IOModeBump IOMode = "bump"
+	// IOModeKeep: старое правило 4b — задача, отдавшая процессор до конца кванта,
+	// остается на своем уровне, квант и лимит квантов уровня сбрасываются
+	IOModeKeep IOMode = "keep"
+	// IOModeAccount: уточненное правило 4 — использованное время продолжает
+	// учитываться в лимите уровня и после I/O
+	IOModeAccount IOMode = "account"
+)
+
+// parseIOMode разбирает режим учета I/O из командной строки
+func parseIOMode(s string) (IOMode, error) {
+	switch mode := IOMode(s); mode {
+	case IOModeBump, IOModeKeep, IOModeAccount:
+		return mode, nil
+	}
+	return "", fmt.Errorf("неизвестный режим учета I/O: %s (доступны: bump, keep, account)", s)
+}
+
+// gamingShare — доля кванта, после которой "игровая" задача выполняет I/O
+const gamingShare = 0.99
+
 // Job представляет задачу в системе
 type Job struct {
 	ID            uint
+	Name          string // Имя задачи из файла рабочей нагрузки (может быть пустым)
+	Class         string // Класс задачи (см. classes.go; может быть пустым)
 	ArrivalTime   uint
 	JobLength     uint
-	IOFrequency   uint // 0 означает отсутствие I/O
+	IOFrequency   uint      // 0 означает отсутствие I/O; при случайном I/O — средняя длина вычислений между I/O
+	IODuration    uint      // Длительность I/O задачи (0 — общая IODuration планировщика)
+	IOBursts      []IOBurst // Явные операции I/O (из трассы или bursts), в порядке выполнения
 	CurrentQueue  uint
 	TimeLeft      uint
 	StartTime     int // -1 если еще не запущена
@@ -22,41 +57,114 @@ type Job struct {
 	TotalWait     uint
 	LastRun       uint
 	TimeSliceLeft uint
-	IOEndTime     uint // Время завершения I/O операции
+	AllotmentLeft uint  // Оставшееся количество квантов на текущем уровне
+	IOEndTime     uint  // Время завершения I/O операции
+	IOBurstLeft   uint  // Случайный I/O: единиц выполнения до следующей операции I/O
+	InitialQueue  uint  // Уровень, на который задача попадает при прибытии
+	Ceiling       uint  // Самый высокий допустимый уровень задачи
+	Floor         *uint // Самый низкий допустимый уровень задачи (nil — без ограничения)
+	CPU           int   // CPU, за которым закреплена задача (очереди на каждом CPU)
+	LastCPU       int   // CPU, на котором задача выполнялась последней (-1 — еще не выполнялась)
+	Gaming        bool  // Задача выполняет I/O на 99% кванта, чтобы сохранить приоритет
+	Done          bool  // Задача завершена
+
+	// Статистика выполнения
+	QueueTime  []uint // Время (ожидание и выполнение) на каждом уровне
+	Demotions  uint   // Количество понижений приоритета
+	IOCount    uint   // Количество операций I/O
+	Migrations uint   // Количество запусков не на том CPU, где задача выполнялась последней
+
+	// Голодание: время в очереди без процессора подряд
+	MaxWaitStreak uint // Самый длинный перерыв
+	MaxWaitStart  uint // Начало самого длинного перерыва
+
+	// Служебные поля очередей и календаря (см. queue.go)
+	prev, next *Job // Соседи в очереди уровня
+	queuedAt   uint // Момент постановки в очередь или последнего учета времени
+	ranInQueue uint // Сколько задача выполнялась с момента queuedAt
+	readySince uint // Начало текущего перерыва без CPU
+	doingIO    bool // Задача выполняет I/O
+	ioReady    uint // Момент возврата из I/O в очередь
+	ioSeq      uint // Порядковый номер операции I/O
+	nextBurst  int  // Следующая операция из IOBursts
+
+	rand       *rand.Rand      // Случайный I/O: генератор задачи (см. jobRand)
+	randSource *countingSource // Источник rand с подсчетом выборок для контрольных точек
 }
 
 // MLFQ представляет многоуровневый планировщик
 type MLFQ struct {
-	Queues      [][]uint // ID задач в каждой очереди
+	Queues      []*JobQueue // Задачи каждого уровня
 	NumQueues   uint
 	TimeSlice   []uint // Временной квант для каждой очереди
+	Allotment   []uint // Количество квантов на уровне до понижения приоритета
+	DemoteLevel []uint // Уровень после исчерпания лимита (nil — следующий уровень)
+	SleepLevel  []uint // Уровень после I/O в режиме bump (nil — уровень 0)
 	BoostTime   uint   // Время для повышения приоритета
 	Jobs        map[uint]*Job
 	CurrentTime uint
 	LastBoost   uint
-	IOQueue     []uint // Задачи, ожидающие завершения I/O
 	PendingJobs []*Job // Задачи, которые еще не прибыли
 	IODuration  uint   // Длительность I/O операции
+	IOMode      IOMode // Учет процессорного времени при I/O
+	RandomIO    bool   // I/O случайный: длины вычислений и задержки устройства геометрические
+	BusyTime    uint   // Сколько единиц времени процессор выполнял задачи
+
+	// Многопроцессорный режим (см. multicpu.go)
+	CPUs            uint
+	PerCPU          bool   // Отдельные очереди на каждом CPU вместо общих
+	Stealing        bool   // Простаивающий CPU забирает задачу у самого загруженного
+	BalanceInterval uint   // Период выравнивания нагрузки (0 = отключено)
+	CPUBusy         []uint // Время выполнения задач на каждом CPU
+	Migrations      uint
+
+	// ShortestFirst: из очереди выбирается задача с наименьшим оставшимся
+	// временем, а не первая (SJF, см. compare.go)
+	ShortestFirst bool
+
+	// MaxStep ограничивает количество единиц времени, пропускаемых за один шаг
+	// симуляции (0 — без ограничения). 1 — строго по одному такту, как нужно
+	// интерактивной визуализации.
+	MaxStep uint
+
+	randSeed        int64      // Семя случайного I/O (SetRandomIO)
+	ioJobs          ioCalendar // Задачи в I/O
+	ioSeq           uint       // Номер следующей операции I/O
+	pendingUnsorted bool       // PendingJobs нужно упорядочить по времени прибытия
+	stopped         bool       // Симуляция остановлена (Stop)
+
+	StarvationThreshold uint // Порог перерыва без CPU для предупреждения о голодании (0 = отключено)
+	Observers           []Observer
 }
 
-// NewMLFQ создает новый MLFQ планировщик
-func NewMLFQ(numQueues uint, timeSlices []uint, boostTime uint, ioDuration uint) *MLFQ {
-	queues := make([][]uint, numQueues)
+// NewMLFQ создает новый MLFQ планировщик.
+// Если allotments == nil, задача получает один квант на каждом уровне.
+func NewMLFQ(numQueues uint, timeSlices []uint, allotments []uint, boostTime uint, ioDuration uint) *MLFQ {
+	queues := make([]*JobQueue, numQueues)
 	for i := range queues {
-		queues[i] = make([]uint, 0)
+		queues[i] = &JobQueue{}
+	}
+	if allotments == nil {
+		allotments = make([]uint, numQueues)
+		for i := range allotments {
+			allotments[i] = 1
+		}
 	}
 
 	return &MLFQ{
 		Queues:      queues,
 		NumQueues:   numQueues,
 		TimeSlice:   timeSlices,
+		Allotment:   allotments,
 		BoostTime:   boostTime,
 		Jobs:        make(map[uint]*Job),
 		CurrentTime: 0,
 		LastBoost:   0,
-		IOQueue:     make([]uint, 0),
 		PendingJobs: make([]*Job, 0),
 		IODuration:  ioDuration,
+		IOMode:      IOModeBump,
+		CPUs:        1,
+		CPUBusy:     make([]uint, 1),
 	}
 }
 
@@ -64,76 +172,145 @@ func NewMLFQ(numQueues uint, timeSlices []uint, boostTime uint, ioDuration uint)
 func (m *MLFQ) AddPendingJob(job *Job) {
 	job.StartTime = -1
 	job.TimeLeft = job.JobLength
+	if n := len(m.PendingJobs); n > 0 && job.ArrivalTime < m.PendingJobs[n-1].ArrivalTime {
+		m.pendingUnsorted = true
+	}
 	m.PendingJobs = append(m.PendingJobs, job)
 }
 
 // AddJob добавляет новую задачу в планировщик (когда она прибывает)
 func (m *MLFQ) AddJob(job *Job) {
 	m.Jobs[job.ID] = job
-	m.Queues[0] = append(m.Queues[0], job.ID) // Новые задачи идут в очередь с наивысшим приоритетом
-	job.CurrentQueue = 0
+	job.LastCPU = -1
+	m.placeJob(job)
+	// Новые задачи идут в очередь с наивысшим приоритетом, если подсказки не требуют иного
+	m.setLevel(job, m.clampLevel(job, job.InitialQueue))
 	job.TimeLeft = job.JobLength
-	job.TimeSliceLeft = m.TimeSlice[0]
+	job.QueueTime = make([]uint, m.NumQueues)
+	m.enqueue(job)
+	job.readySince = m.CurrentTime
+	if m.RandomIO && job.IOFrequency > 0 {
+		job.IOBurstLeft = m.geometric(job, job.IOFrequency)
+	}
+	m.emit(Event{Type: EventArrival, Time: m.CurrentTime, JobID: job.ID, To: job.CurrentQueue})
+}
+
+// setLevel переводит задачу на уровень и выдает ей полный квант и лимит квантов этого уровня
+func (m *MLFQ) setLevel(job *Job, level uint) {
+	job.CurrentQueue = level
+	job.TimeSliceLeft = m.TimeSlice[level]
+	job.AllotmentLeft = m.Allotment[level]
 }
 
 // CheckArrivals проверяет прибывающие задачи
 func (m *MLFQ) CheckArrivals() {
-	newPending := make([]*Job, 0)
-	for _, job := range m.PendingJobs {
-		if job.ArrivalTime == m.CurrentTime {
-			m.AddJob(job)
-		} else if job.ArrivalTime > m.CurrentTime {
-			newPending = append(newPending, job)
-		}
+	for job := m.nextArrival(); job != nil; job = m.nextArrival() {
+		m.AddJob(job)
 	}
-	m.PendingJobs = newPending
 }
 
 // MoveJobToLowerQueue перемещает задачу в очередь с более низким приоритетом
 func (m *MLFQ) MoveJobToLowerQueue(jobID uint) {
 	job := m.Jobs[jobID]
-	currentQueue := job.CurrentQueue
 
 	// Удаляем из текущей очереди
-	for i, id := range m.Queues[currentQueue] {
-		if id == jobID {
-			m.Queues[currentQueue] = append(m.Queues[currentQueue][:i], m.Queues[currentQueue][i+1:]...)
-			break
-		}
-	}
+	m.removeFromQueue(job)
 
 	// Добавляем в очередь с более низким приоритетом (если она существует)
-	if currentQueue < m.NumQueues-1 {
-		job.CurrentQueue = currentQueue + 1
-		m.Queues[currentQueue+1] = append(m.Queues[currentQueue+1], jobID)
-		job.TimeSliceLeft = m.TimeSlice[currentQueue+1]
-	} else {
-		// Остается в самой низкой очереди
-		m.Queues[currentQueue] = append(m.Queues[currentQueue], jobID)
-		job.TimeSliceLeft = m.TimeSlice[currentQueue]
+	m.demote(job)
+	m.enqueue(job)
+}
+
+// demote переводит задачу на следующий уровень и учитывает понижение
+func (m *MLFQ) demote(job *Job) {
+	from := job.CurrentQueue
+	level := m.clampLevel(job, m.lowerLevel(from))
+	m.setLevel(job, level)
+	if level != from {
+		job.Demotions++
+		m.emit(Event{Type: EventDemote, Time: m.CurrentTime, JobID: job.ID, From: from, To: level})
+	}
+}
+
+// lowerLevel возвращает уровень, на который понижается задача с уровня level
+func (m *MLFQ) lowerLevel(level uint) uint {
+	if m.DemoteLevel != nil {
+		return m.DemoteLevel[level]
+	}
+	if level < m.NumQueues-1 {
+		return level + 1
+	}
+	// Остается в самой низкой очереди
+	return level
+}
+
+// sleepLevel возвращает уровень, на который задача с уровня level
+// возвращается после I/O в режиме bump
+func (m *MLFQ) sleepLevel(level uint) uint {
+	if m.SleepLevel != nil {
+		return m.SleepLevel[level]
 	}
+	return 0
+}
+
+// useAllotment списывает истекший квант с лимита уровня и возвращает true,
+// если задача исчерпала лимит и должна быть понижена
+func (m *MLFQ) useAllotment(job *Job) bool {
+	if job.AllotmentLeft > 0 {
+		job.AllotmentLeft--
+	}
+	return job.AllotmentLeft == 0
+}
+
+// ExpireTimeSlice обрабатывает истечение кванта: задача, исчерпавшая лимит
+// квантов уровня, понижается, иначе получает новый квант и встает в конец своей очереди
+func (m *MLFQ) ExpireTimeSlice(jobID uint) {
+	job := m.Jobs[jobID]
+	if m.useAllotment(job) {
+		m.MoveJobToLowerQueue(jobID)
+		return
+	}
+
+	m.removeFromQueue(job)
+	m.enqueue(job)
+	job.TimeSliceLeft = m.TimeSlice[job.CurrentQueue]
 }
 
-// BoostAllJobs повышает приоритет всех задач до наивысшей очереди
+// BoostAllJobs повышает приоритет всех задач, в том числе выполняющих I/O,
+// до наивысшей очереди (или до уровня ceil задачи) с новым квантом и лимитом.
+// Как в mlfq.py, первыми в конец очереди 0 встают задачи с самых низких уровней.
 func (m *MLFQ) BoostAllJobs() {
-	for i := uint(1); i < m.NumQueues; i++ {
-		for _, jobID := range m.Queues[i] {
-			job := m.Jobs[jobID]
-			job.CurrentQueue = 0
-			job.TimeSliceLeft = m.TimeSlice[0]
-			m.Queues[0] = append(m.Queues[0], jobID)
+	for i := int(m.NumQueues) - 1; i >= 0; i-- {
+		level := uint(i)
+		for job := m.Queues[level].Front(); job != nil; {
+			next := job.next
+			target := m.clampLevel(job, 0)
+			if target == level {
+				m.setLevel(job, target)
+			} else {
+				m.removeFromQueue(job)
+				m.setLevel(job, target)
+				m.enqueue(job)
+			}
+			job = next
 		}
-		m.Queues[i] = m.Queues[i][:0] // Очищаем очередь
+	}
+	for _, job := range m.ioJobs {
+		m.setLevel(job, m.clampLevel(job, 0))
 	}
 	m.LastBoost = m.CurrentTime
+	m.emit(Event{Type: EventBoost, Time: m.CurrentTime})
 }
 
 // GetNextJob возвращает следующую задачу для выполнения
 func (m *MLFQ) GetNextJob() *Job {
-	for i := uint(0); i < m.NumQueues; i++ {
-		if len(m.Queues[i]) > 0 {
-			jobID := m.Queues[i][0]
-			return m.Jobs[jobID]
+	for _, queue := range m.Queues {
+		job := queue.Front()
+		if m.ShortestFirst {
+			job = m.shortestJob(queue)
+		}
+		if job != nil {
+			return job
 		}
 	}
 	return nil
@@ -141,100 +318,219 @@ func (m *MLFQ) GetNextJob() *Job {
 
 // HandleIO обрабатывает завершение I/O операций
 func (m *MLFQ) HandleIO() {
-	newIOQueue := make([]uint, 0)
-	for _, jobID := range m.IOQueue {
-		job := m.Jobs[jobID]
-		if m.CurrentTime >= job.IOEndTime {
-			// Возвращаем задачу в очередь с наивысшим приоритетом
-			job.CurrentQueue = 0
-			job.TimeSliceLeft = m.TimeSlice[0]
-			m.Queues[0] = append(m.Queues[0], jobID)
-		} else {
-			newIOQueue = append(newIOQueue, jobID)
+	for job := m.nextIOComplete(); job != nil; job = m.nextIOComplete() {
+		from := job.CurrentQueue
+		if m.IOMode == IOModeBump {
+			// Возвращаем задачу в очередь с более высоким приоритетом
+			m.setLevel(job, m.clampLevel(job, m.sleepLevel(job.CurrentQueue)))
 		}
+		m.enqueue(job)
+		job.readySince = m.CurrentTime
+		m.emit(Event{Type: EventIOComplete, Time: m.CurrentTime, JobID: job.ID, From: from, To: job.CurrentQueue})
 	}
-	m.IOQueue = newIOQueue
 }
 
-// Run запускает симуляцию планировщика
-func (m *MLFQ) Run(maxTime uint) {
-	fmt.Printf("Время: %4s | Выполняется: %4s | Очереди: %s\n", "T", "Job", "Q0:Q1:Q2")
-	fmt.Println("------------------------------------------------------------")
+// NeedsIO проверяет, начинает ли задача I/O после очередной единицы выполнения
+func (m *MLFQ) NeedsIO(job *Job) bool {
+	if _, ok := job.dueBurst(); ok {
+		return true
+	}
+	if job.IOFrequency > 0 {
+		if m.RandomIO {
+			if job.IOBurstLeft == 0 {
+				return true
+			}
+		} else if executed := job.JobLength - job.TimeLeft; executed > 0 && executed%job.IOFrequency == 0 {
+			return true
+		}
+	}
+	if job.Gaming && job.TimeSliceLeft > 0 {
+		// Уходим в I/O, использовав 99% кванта
+		slice := m.TimeSlice[job.CurrentQueue]
+		used := slice - job.TimeSliceLeft
+		threshold := uint(float64(slice) * gamingShare)
+		if threshold == 0 {
+			threshold = 1
+		}
+		return used >= threshold
+	}
+	return false
+}
 
-	for m.CurrentTime < maxTime {
-		// Проверяем прибывающие задачи
-		m.CheckArrivals()
+// StartIO снимает задачу с процессора на время I/O с учетом режима IOMode
+func (m *MLFQ) StartIO(job *Job) {
+	queue := job.CurrentQueue
+	m.removeFromQueue(job)
+	job.IOCount++
+
+	switch m.IOMode {
+	case IOModeKeep:
+		// Отдав процессор до конца кванта, задача сохраняет уровень и получает
+		// новый квант. Если квант истек одновременно с началом I/O, задача
+		// процессор не отдавала, и квант списывается как обычно.
+		expired := job.TimeSliceLeft == 0
+		m.setLevel(job, queue)
+		if expired && m.useAllotment(job) {
+			m.demote(job)
+		}
+	case IOModeAccount:
+		// Квант мог закончиться одновременно с началом I/O — списываем его сразу
+		if job.TimeSliceLeft == 0 {
+			if m.useAllotment(job) {
+				m.demote(job)
+			} else {
+				job.TimeSliceLeft = m.TimeSlice[queue]
+			}
+		}
+	}
+
+	job.IOEndTime = m.CurrentTime + m.ioDuration(job)
+	if _, ok := job.dueBurst(); ok {
+		job.nextBurst++
+	}
+	if m.RandomIO && job.IOFrequency > 0 {
+		job.IOBurstLeft = m.geometric(job, job.IOFrequency)
+	}
+	m.scheduleIO(job)
+	m.emit(Event{Type: EventIOStart, Time: m.CurrentTime, JobID: job.ID, From: queue, To: job.CurrentQueue})
+}
+
+// ioDuration возвращает длительность очередной операции I/O задачи
+func (m *MLFQ) ioDuration(job *Job) uint {
+	if burst, ok := job.dueBurst(); ok {
+		// Длительность операции из трассы известна точно
+		return burst.Duration
+	}
+	duration := m.IODuration
+	if job.IODuration > 0 {
+		duration = job.IODuration
+	}
+	if m.RandomIO && duration > 0 {
+		// Задержка устройства меняется от операции к операции
+		duration = m.geometric(job, duration)
+	}
+	return duration
+}
+
+// geometric возвращает случайное число из геометрического распределения
+// на {1, 2, ...} со средним mean, выбранное генератором задачи
+func (m *MLFQ) geometric(job *Job, mean uint) uint {
+	if mean <= 1 {
+		return 1
+	}
+	p := 1 / float64(mean)
+	u := 1 - m.jobRand(job).Float64() // (0, 1]
+	return 1 + uint(math.Floor(math.Log(u)/math.Log(1-p)))
+}
+
+// AllDone проверяет, что не осталось ни ожидающих прибытия, ни активных задач
+func (m *MLFQ) AllDone() bool {
+	if len(m.PendingJobs) > 0 || len(m.ioJobs) > 0 {
+		return false
+	}
+	for _, queue := range m.Queues {
+		if queue.Len() > 0 {
+			return false
+		}
+	}
+	return true
+}
 
+// Run запускает симуляцию планировщика до завершения всех задач.
+// maxTime ограничивает время симуляции (0 — без ограничения).
+// Ход симуляции передается наблюдателям из Observers. На одном CPU, пока
+// состояние очередей не меняется, симуляция продвигается сразу на несколько
+// единиц времени, а наблюдатели получают их одним событием с Duration.
+func (m *MLFQ) Run(maxTime uint) {
+	for (maxTime == 0 || m.CurrentTime < maxTime) && !m.AllDone() && !m.stopped {
 		// Проверяем повышение приоритета
 		if m.BoostTime > 0 && m.CurrentTime >= m.LastBoost+m.BoostTime {
 			m.BoostAllJobs()
 		}
 
+		// Проверяем прибывающие задачи
+		m.CheckArrivals()
+
 		// Обрабатываем завершение I/O операций
 		m.HandleIO()
 
-		// Получаем следующую задачу
-		currentJob := m.GetNextJob()
-
-		if currentJob == nil {
-			fmt.Printf("%4d | %8s | %s\n", m.CurrentTime, "IDLE", m.getQueueStatus())
-			m.CurrentTime++
-			continue
+		// Выравниваем нагрузку очередей CPU
+		if m.PerCPU && m.BalanceInterval > 0 && m.CurrentTime > 0 && m.CurrentTime%m.BalanceInterval == 0 {
+			m.balance()
 		}
 
-		// Если задача только начинается
-		if currentJob.StartTime == -1 {
-			currentJob.StartTime = int(m.CurrentTime)
+		// Получаем следующую задачу для каждого CPU
+		running := m.dispatch()
+		step := uint(1)
+		if m.CPUs == 1 {
+			step = m.stepLength(running[0], maxTime)
 		}
 
-		fmt.Printf("%4d | %8d | %s\n", m.CurrentTime, currentJob.ID, m.getQueueStatus())
-
-		// Выполняем задачу
-		currentJob.TimeLeft--
-		currentJob.TimeSliceLeft--
-		currentJob.LastRun = m.CurrentTime
-		m.CurrentTime++
+		now := m.CurrentTime
+		for cpu, currentJob := range running {
+			if currentJob == nil {
+				m.emit(Event{Type: EventIdle, Time: now, CPU: uint(cpu), Duration: step})
+				continue
+			}
 
-		// Проверяем завершение задачи
-		if currentJob.TimeLeft == 0 {
-			currentJob.EndTime = m.CurrentTime - 1
-			fmt.Printf("Задача %d завершена в время %d\n", currentJob.ID, currentJob.EndTime)
-			// Удаляем из очереди
-			queue := currentJob.CurrentQueue
-			for i, id := range m.Queues[queue] {
-				if id == currentJob.ID {
-					m.Queues[queue] = append(m.Queues[queue][:i], m.Queues[queue][i+1:]...)
-					break
-				}
+			// Если задача только начинается
+			if currentJob.StartTime == -1 {
+				currentJob.StartTime = int(now)
 			}
-			continue
+			if currentJob.LastCPU >= 0 && currentJob.LastCPU != cpu {
+				currentJob.Migrations++
+				m.Migrations++
+			}
+			currentJob.LastCPU = cpu
+			endWait(currentJob, now)
+
+			m.emit(Event{Type: EventDispatch, Time: now, JobID: currentJob.ID, To: currentJob.CurrentQueue, CPU: uint(cpu), Duration: step})
+
+			// Выполняем задачу
+			currentJob.TimeLeft -= step
+			currentJob.TimeSliceLeft -= step
+			currentJob.IOBurstLeft -= min(step, currentJob.IOBurstLeft)
+			currentJob.LastRun = now + step - 1
+			currentJob.ranInQueue += step
+			currentJob.readySince = now + step
+			m.BusyTime += step
+			m.CPUBusy[cpu] += step
 		}
+		m.CurrentTime += step
 
-		// Проверяем I/O операцию
-		if currentJob.IOFrequency > 0 {
-			executed := currentJob.JobLength - currentJob.TimeLeft
-			if executed > 0 && executed%currentJob.IOFrequency == 0 {
-				// Задача выполняет I/O
-				queue := currentJob.CurrentQueue
-				for i, id := range m.Queues[queue] {
-					if id == currentJob.ID {
-						m.Queues[queue] = append(m.Queues[queue][:i], m.Queues[queue][i+1:]...)
-						break
-					}
-				}
-				currentJob.IOEndTime = m.CurrentTime + m.IODuration - 1
-				m.IOQueue = append(m.IOQueue, currentJob.ID)
+		for _, currentJob := range running {
+			if currentJob == nil {
 				continue
 			}
-		}
 
-		// Проверяем истечение временного кванта
-		if currentJob.TimeSliceLeft == 0 {
-			m.MoveJobToLowerQueue(currentJob.ID)
+			// Проверяем завершение задачи
+			if currentJob.TimeLeft == 0 {
+				currentJob.EndTime = m.CurrentTime
+				currentJob.Done = true
+				// Удаляем из очереди
+				m.removeFromQueue(currentJob)
+				m.emit(Event{Type: EventFinish, Time: currentJob.EndTime, JobID: currentJob.ID, To: currentJob.CurrentQueue})
+				continue
+			}
+
+			// Проверяем I/O операцию
+			if m.NeedsIO(currentJob) {
+				m.StartIO(currentJob)
+				continue
+			}
+
+			// Проверяем истечение временного кванта
+			if currentJob.TimeSliceLeft == 0 {
+				m.ExpireTimeSlice(currentJob.ID)
+			}
 		}
 	}
+	m.settleAll()
+}
 
-	m.PrintStatistics()
+// Stop останавливает симуляцию после текущего такта
+func (m *MLFQ) Stop() {
+	m.stopped = true
 }
 
 // getQueueStatus возвращает статус всех очередей
@@ -244,43 +540,131 @@ func (m *MLFQ) getQueueStatus() string {
 		if i > 0 {
 			status += ":"
 		}
-		if len(queue) == 0 {
+		if queue.Len() == 0 {
 			status += "[]"
 		} else {
-			status += fmt.Sprintf("%v", queue)
+			status += fmt.Sprintf("%v", queue.IDs())
 		}
 	}
 	return status
 }
 
+// SortedJobs возвращает прибывшие задачи, отсортированные по ID
+func (m *MLFQ) SortedJobs() []*Job {
+	jobs := make([]*Job, 0, len(m.Jobs))
+	for _, job := range m.Jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs
+}
+
 // PrintStatistics выводит статистику выполнения
 func (m *MLFQ) PrintStatistics() {
 	fmt.Println("\n=== Статистика ===")
 
-	var completedJobs []*Job
+	var completedJobs, unfinished []*Job
 	var totalTurnaround uint = 0
 	var totalResponse int = 0
+	var totalWait uint = 0
 
-	for _, job := range m.Jobs {
-		if job.EndTime > 0 && job.StartTime >= 0 {
-			completedJobs = append(completedJobs, job)
-			turnaround := job.EndTime - job.ArrivalTime + 1
-			response := job.StartTime - int(job.ArrivalTime)
-			totalTurnaround += turnaround
-			totalResponse += response
+	// Имена задач из файла рабочей нагрузки выводятся отдельным столбцом
+	nameWidth := 0
+	for _, job := range m.SortedJobs() {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(job.Name))
+	}
+	nameColumn := func(name string) string {
+		if nameWidth == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%-*s ", max(nameWidth, 3), name)
+	}
 
-			fmt.Printf("Задача %d: Время отклика = %d, Время выполнения = %d\n",
-				job.ID, response, turnaround)
+	fmt.Printf("%-6s %s%-9s %-6s %-7s %-9s %-9s %-10s %-5s %s\n",
+		"Задача", nameColumn("Имя"), "Прибытие", "Длина", "Отклик", "Оборотное", "Ожидание", "Понижений", "I/O", "Время на уровнях")
+	for _, job := range m.SortedJobs() {
+		if !job.Done {
+			unfinished = append(unfinished, job)
+			continue
 		}
+		completedJobs = append(completedJobs, job)
+		turnaround := job.EndTime - job.ArrivalTime
+		response := job.StartTime - int(job.ArrivalTime)
+		totalTurnaround += turnaround
+		totalResponse += response
+		totalWait += job.TotalWait
+
+		fmt.Printf("%-6d %s%-9d %-6d %-7d %-9d %-9d %-10d %-5d %v\n",
+			job.ID, nameColumn(job.Name), job.ArrivalTime, job.JobLength, response, turnaround,
+			job.TotalWait, job.Demotions, job.IOCount, job.QueueTime)
 	}
 
 	if len(completedJobs) > 0 {
 		avgTurnaround := float64(totalTurnaround) / float64(len(completedJobs))
 		avgResponse := float64(totalResponse) / float64(len(completedJobs))
+		avgWait := float64(totalWait) / float64(len(completedJobs))
 
 		fmt.Printf("\nСреднее время выполнения: %.2f\n", avgTurnaround)
 		fmt.Printf("Среднее время отклика: %.2f\n", avgResponse)
+		fmt.Printf("Среднее время ожидания: %.2f\n", avgWait)
 	}
+
+	// Задачи, которые не прибыли до конца симуляции, тоже не завершены
+	pending := make([]*Job, len(m.PendingJobs))
+	copy(pending, m.PendingJobs)
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+
+	if len(unfinished)+len(pending) > 0 {
+		fmt.Printf("\nНе завершены к моменту %d: %d из %d задач\n",
+			m.CurrentTime, len(unfinished)+len(pending), len(m.Jobs)+len(m.PendingJobs))
+		for _, job := range unfinished {
+			state := fmt.Sprintf("в очереди %d", job.CurrentQueue)
+			if m.inIO(job.ID) {
+				state = "выполняет I/O"
+			}
+			fmt.Printf("Задача %s: осталось %d из %d, %s, ожидание %d\n",
+				job.Label(), job.TimeLeft, job.JobLength, state, job.TotalWait)
+		}
+		for _, job := range pending {
+			fmt.Printf("Задача %s: не прибыла (прибытие=%d)\n", job.Label(), job.ArrivalTime)
+		}
+	}
+
+	m.printClassStats()
+	m.printCPUShare()
+	m.printCPUStats()
+	m.printStarvation()
+}
+
+// inIO проверяет, выполняет ли задача I/O
+func (m *MLFQ) inIO(jobID uint) bool {
+	job := m.Jobs[jobID]
+	return job != nil && job.doingIO
+}
+
+// printCPUShare выводит, какую долю процессора получили задачи, обманывающие планировщик
+func (m *MLFQ) printCPUShare() {
+	var gamers []*Job
+	for _, job := range m.Jobs {
+		if job.Gaming {
+			gamers = append(gamers, job)
+		}
+	}
+	if len(gamers) == 0 || m.BusyTime == 0 {
+		return
+	}
+	sort.Slice(gamers, func(i, j int) bool { return gamers[i].ID < gamers[j].ID })
+
+	fmt.Printf("\nДоля CPU задач, выполняющих I/O на %.0f%% кванта (режим I/O: %s):\n", gamingShare*100, m.IOMode)
+	var total uint
+	for _, job := range gamers {
+		cpu := job.JobLength - job.TimeLeft
+		total += cpu
+		fmt.Printf("Задача %d: %d из %d единиц занятого процессора (%.1f%%)\n",
+			job.ID, cpu, m.BusyTime, 100*float64(cpu)/float64(m.BusyTime))
+	}
+	fairShare := 100 * float64(len(gamers)) / float64(len(m.Jobs))
+	fmt.Printf("Всего: %.1f%% при равной доле %.1f%%\n", 100*float64(total)/float64(m.BusyTime), fairShare)
 }
 
 // parseWorkload парсит пользовательскую рабочую нагрузку
@@ -292,8 +676,18 @@ func parseWorkload(workload string) ([]*Job, error) {
 		if jobStr == "" {
 			continue
 		}
-		parts := strings.Split(jobStr, ",")
-		if len(parts) < 2 {
+		// Подсказки ключ=значение идут после позиционных полей
+		var parts, hints []string
+		for _, part := range strings.Split(jobStr, ",") {
+			if strings.Contains(part, "=") {
+				hints = append(hints, part)
+			} else if len(hints) > 0 {
+				return nil, fmt.Errorf("поле %q после подсказок: %s", part, jobStr)
+			} else {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) < 2 || len(parts) > 4 {
 			return nil, fmt.Errorf("неверный формат задачи: %s", jobStr)
 		}
 
@@ -303,12 +697,12 @@ func parseWorkload(workload string) ([]*Job, error) {
 		}
 
 		length, err := strconv.ParseUint(parts[1], 10, 32)
-		if err != nil {
-			return nil, fmt.Errorf("неверная длительность: %s", parts[1])
+		if err != nil || length == 0 {
+			return nil, fmt.Errorf("неверная длительность: %s (должна быть больше 0)", parts[1])
 		}
 
 		var ioFreq uint = 0
-		if len(parts) > 2 {
+		if len(parts) > 2 && parts[2] != "" {
 			freq, err := strconv.ParseUint(parts[2], 10, 32)
 			if err != nil {
 				return nil, fmt.Errorf("неверная частота I/O: %s", parts[2])
@@ -316,94 +710,522 @@ func parseWorkload(workload string) ([]*Job, error) {
 			ioFreq = uint(freq)
 		}
 
+		var ioLen uint = 0
+		if len(parts) > 3 && parts[3] != "" {
+			d, err := strconv.ParseUint(parts[3], 10, 32)
+			if err != nil || d == 0 {
+				return nil, fmt.Errorf("неверная длительность I/O: %s", parts[3])
+			}
+			ioLen = uint(d)
+		}
+
 		job := &Job{
 			ID:          uint(i + 1),
 			ArrivalTime: uint(arrival),
 			JobLength:   uint(length),
 			IOFrequency: ioFreq,
+			IODuration:  ioLen,
 			StartTime:   -1,
 		}
+		for _, hint := range hints {
+			if err := parseHint(job, hint); err != nil {
+				return nil, err
+			}
+		}
 		jobs = append(jobs, job)
 	}
 
 	return jobs, nil
 }
 
+// parseUintList парсит список положительных чисел через запятую
+func parseUintList(list string) ([]uint, error) {
+	return parseUintValues(list, 1)
+}
+
+// parseUintValues парсит список чисел через запятую, каждое не меньше minValue
+func parseUintValues(list string, minValue uint) ([]uint, error) {
+	var values []uint
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		v, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint(v) < minValue {
+			return nil, fmt.Errorf("неверное значение: %s", part)
+		}
+		values = append(values, uint(v))
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("пустой список")
+	}
+	return values, nil
+}
+
+// perQueueList возвращает значение для каждой из numQueues очередей:
+// одно значение применяется ко всем очередям, иначе длина списка должна совпадать
+func perQueueList(values []uint, numQueues uint) ([]uint, error) {
+	if len(values) == 1 {
+		list := make([]uint, numQueues)
+		for i := range list {
+			list[i] = values[0]
+		}
+		return list, nil
+	}
+	if uint(len(values)) != numQueues {
+		return nil, fmt.Errorf("ожидается %d значений, получено %d", numQueues, len(values))
+	}
+	return values, nil
+}
+
 func main() {
 	// Параметры командной строки
 	numJobs := flag.Uint("j", 3, "Количество задач")
-	maxTime := flag.Uint("t", 200, "Максимальное время симуляции")
+	maxTime := flag.Uint("t", 0, "Максимальное время симуляции (0 = до завершения всех задач)")
 	ioFreq := flag.Uint("i", 0, "Частота I/O операций (0 = нет I/O)")
 	ioDuration := flag.Uint("I", 5, "Длительность I/O операции")
 	boost := flag.Uint("B", 0, "Период повышения приоритета (0 = отключено)")
 	seed := flag.Int64("s", time.Now().UnixNano(), "Семя для генератора случайных чисел")
-	workload := flag.String("w", "", "Рабочая нагрузка (формат: время_прибытия,длительность,io_частота;...)")
+	workload := flag.String("w", "", "Рабочая нагрузка (формат: время_прибытия,длительность[,io_частота[,длительность_io]][,queue=N][,ceil=N][,floor=N];...)")
+	workloadFile := flag.String("f", "", "Файл рабочей нагрузки с именами задач (см. workload.go)")
+	workloadFormat := flag.String("F", "", "Формат файла -f: lines, csv, json или trace (по умолчанию по расширению)")
 	quantum0 := flag.Uint("q0", 10, "Временной квант для очереди 0")
 	quantum1 := flag.Uint("q1", 20, "Временной квант для очереди 1")
 	quantum2 := flag.Uint("q2", 40, "Временной квант для очереди 2")
-	numQueues := flag.Uint("Q", 3, "Количество очередей")
+	quantumList := flag.String("Q", "", "Кванты для каждой очереди через запятую (например, 10,20,40); задает количество очередей")
+	allotmentList := flag.String("A", "", "Количество квантов на каждом уровне до понижения через запятую (например, 2,2,1)")
+	numQueues := flag.Uint("n", 3, "Количество очередей")
+	ioModeFlag := flag.String("m", "bump", "Учет времени при I/O: bump (возврат в очередь 0), keep (уровень сохраняется, квант сбрасывается), account (время учитывается и после I/O)")
+	gamingList := flag.String("G", "", "ID задач через запятую, которые выполняют I/O на 99% кванта")
+	configFile := flag.String("c", "", "Файл таблицы диспетчеризации (JSON или YAML); заменяет -n, -Q, -A и -q0..-q2")
+	dumpConfig := flag.Bool("D", false, "Вывести конфигурацию планировщика в формате JSON и выйти")
+	tuneMode := flag.String("T", "", "Подбор параметров: grid (полный перебор) или random (случайный поиск)")
+	objective := flag.String("o", "response", "Целевая функция подбора: response (отклик интерактивных задач), turnaround (оборотное время пакетных), starvation (макс. перерыв без CPU)")
+	tuneQueues := flag.String("Tn", "2,3,4", "Подбор: допустимое количество очередей")
+	tuneQuanta := flag.String("Tq", "5,10,20,40", "Подбор: допустимые значения квантов")
+	tuneBoosts := flag.String("TB", "0,50,100,200", "Подбор: допустимые периоды повышения приоритета (0 = отключено)")
+	tuneSamples := flag.Int("Tr", 50, "Подбор: количество конфигураций для random")
+	tuneWorkloads := flag.Int("Tw", 5, "Подбор: количество случайных нагрузок, если не задана -w")
+	tuneTop := flag.Int("Tk", 5, "Подбор: сколько лучших конфигураций выводить")
+	workers := flag.Int("P", 0, "Подбор: количество воркеров (0 = количество CPU)")
+	randomIO := flag.Bool("r", false, "Случайный I/O: геометрические интервалы вычислений со средним io_частота и задержки устройства со средним длительность_io (семя -s)")
+	cpus := flag.Uint("C", 1, "Количество процессоров")
+	cpuMode := flag.String("M", "shared", "Очереди при нескольких CPU: shared (общие) или percpu (на каждом CPU)")
+	stealing := flag.Bool("S", false, "percpu: простаивающий CPU забирает задачу у самого загруженного")
+	balanceInterval := flag.Uint("L", 0, "percpu: период выравнивания нагрузки (0 = отключено)")
+	window := flag.Uint("W", 50, "Размер скользящего окна для доли CPU задач (0 = не выводить)")
+	threshold := flag.Uint("X", 0, "Порог голодания: предупреждать о задачах, ждавших процессор дольше (0 = отключено)")
+	tuiSpeed := flag.Int("U", 0, "Интерактивная визуализация в терминале с заданной скоростью, тактов в секунду (0 = текстовый вывод)")
+	checkpointFile := flag.String("k", "", "Сохранить контрольную точку симуляции в файл в момент -K")
+	checkpointTime := flag.Uint("K", 0, "Момент сохранения контрольной точки -k")
+	resumeFile := flag.String("R", "", "Продолжить симуляцию из контрольной точки; -B, -Q, -q0..-q2, -A, -c, -I, -m задают новую политику")
+	quiet := flag.Bool("q", false, "Не выводить задачи и ход симуляции, только статистику (доля CPU в окнах — только с явным -W)")
+	comparison := flag.String("p", "", "Сравнить с политиками на той же нагрузке, через запятую: fifo, sjf, rr (квант очереди 0), rr:N")
+	verbose := flag.Bool("v", false, "Выводить все события планировщика (прибытие, понижение, повышение, I/O)")
 	arrivalTime := flag.Uint("a", 20, "Максимальное время прибытия для случайных задач")
 	jobLength := flag.Uint("l", 50, "Максимальная длительность для случайных задач")
+	classList := flag.String("J", "", "Классы случайных задач с весами, например interactive:3,batch:1,gamer:1 (см. classes.go); заменяет -l и -i")
 
 	flag.Parse()
 
-	rand.Seed(*seed)
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+	numQueuesSet := setFlags["n"]
+
+	// При продолжении из контрольной точки количество уровней берется из нее
+	var resumed *MLFQ
+	if *resumeFile != "" {
+		for _, name := range []string{"n", "w", "f", "j", "C", "M", "r", "T", "p", "J"} {
+			if setFlags[name] {
+				fmt.Printf("Ошибка: флаг -%s нельзя сочетать с -R\n", name)
+				return
+			}
+		}
+		var err error
+		if resumed, err = LoadCheckpoint(*resumeFile); err != nil {
+			fmt.Printf("Ошибка чтения контрольной точки: %v\n", err)
+			return
+		}
+		*numQueues = resumed.NumQueues
+		numQueuesSet = true
+	}
 
 	// Создаем временные кванты на основе флагов
 	var timeSlices []uint
-	if *numQueues >= 1 {
-		timeSlices = append(timeSlices, *quantum0)
-	}
-	if *numQueues >= 2 {
-		timeSlices = append(timeSlices, *quantum1)
+	if *quantumList != "" {
+		quanta, err := parseUintList(*quantumList)
+		if err != nil {
+			fmt.Printf("Ошибка в списке квантов: %v\n", err)
+			return
+		}
+		if !numQueuesSet && len(quanta) > 1 {
+			*numQueues = uint(len(quanta))
+		}
+		timeSlices, err = perQueueList(quanta, *numQueues)
+		if err != nil {
+			fmt.Printf("Ошибка в списке квантов: %v\n", err)
+			return
+		}
+	} else {
+		if *numQueues >= 1 {
+			timeSlices = append(timeSlices, *quantum0)
+		}
+		if *numQueues >= 2 {
+			timeSlices = append(timeSlices, *quantum1)
+		}
+		if *numQueues >= 3 {
+			timeSlices = append(timeSlices, *quantum2)
+		}
+		// Без -Q для дополнительных очередей используем последний квант
+		for len(timeSlices) > 0 && uint(len(timeSlices)) < *numQueues {
+			timeSlices = append(timeSlices, timeSlices[len(timeSlices)-1])
+		}
 	}
-	if *numQueues >= 3 {
-		timeSlices = append(timeSlices, *quantum2)
+	if *numQueues == 0 {
+		fmt.Println("Ошибка: нужна хотя бы одна очередь")
+		return
 	}
-	// Для дополнительных очередей используем последний квант
-	for uint(len(timeSlices)) < *numQueues {
-		timeSlices = append(timeSlices, timeSlices[len(timeSlices)-1])
+
+	var allotments []uint
+	if *allotmentList != "" {
+		list, err := parseUintList(*allotmentList)
+		if err == nil {
+			allotments, err = perQueueList(list, *numQueues)
+		}
+		if err != nil {
+			fmt.Printf("Ошибка в списке квантов на уровень: %v\n", err)
+			return
+		}
 	}
 
-	// Создаем MLFQ планировщик
-	scheduler := NewMLFQ(*numQueues, timeSlices, *boost, *ioDuration)
+	ioMode, err := parseIOMode(*ioModeFlag)
+	if err != nil {
+		fmt.Printf("Ошибка: %v\n", err)
+		return
+	}
 
-	// Добавляем задачи
-	if *workload != "" {
-		// Парсим пользовательскую рабочую нагрузку
-		jobs, err := parseWorkload(*workload)
+	gaming := make(map[uint]bool)
+	if *gamingList != "" {
+		ids, err := parseUintList(*gamingList)
 		if err != nil {
+			fmt.Printf("Ошибка в списке задач: %v\n", err)
+			return
+		}
+		for _, id := range ids {
+			gaming[id] = true
+		}
+	}
+
+	// Заданная рабочая нагрузка: из -w или из файла -f
+	var jobs []*Job
+	switch {
+	case *workload != "" && *workloadFile != "":
+		fmt.Println("Ошибка: -w и -f нельзя задавать вместе")
+		return
+	case *workload != "":
+		if jobs, err = parseWorkload(*workload); err != nil {
 			fmt.Printf("Ошибка парсинга рабочей нагрузки: %v\n", err)
 			return
 		}
+	case *workloadFile != "":
+		if jobs, err = LoadWorkload(*workloadFile, *workloadFormat); err != nil {
+			fmt.Printf("Ошибка чтения рабочей нагрузки: %v\n", err)
+			return
+		}
+	}
+	var classes []JobClass
+	if *classList != "" {
+		if jobs != nil {
+			fmt.Println("Ошибка: классы задач (-J) задают случайную нагрузку и не сочетаются с -w и -f")
+			return
+		}
+		if classes, err = parseClasses(*classList); err != nil {
+			fmt.Printf("Ошибка в списке классов: %v\n", err)
+			return
+		}
+	}
+	for _, job := range jobs {
+		job.Gaming = gaming[job.ID]
+	}
+
+	if *tuneMode != "" {
+		runTune(tuneOptions{
+			mode: *tuneMode, objective: *objective,
+			queues: *tuneQueues, quanta: *tuneQuanta, boosts: *tuneBoosts,
+			samples: *tuneSamples, workloads: *tuneWorkloads, top: *tuneTop, workers: *workers,
+			jobs: jobs, classes: classes, seed: *seed,
+			numJobs: *numJobs, maxArrival: *arrivalTime, maxLength: *jobLength, ioFreq: *ioFreq,
+			ioDuration: *ioDuration, ioMode: ioMode, randomIO: *randomIO,
+		})
+		return
+	}
+
+	// Создаем MLFQ планировщик
+	var cfg *Config
+	if *configFile != "" {
+		for _, name := range []string{"n", "Q", "A", "q0", "q1", "q2"} {
+			if setFlags[name] {
+				fmt.Printf("Ошибка: флаг -%s нельзя сочетать с -c\n", name)
+				return
+			}
+		}
+		if cfg, err = LoadConfig(*configFile); err != nil {
+			fmt.Printf("Ошибка чтения конфигурации: %v\n", err)
+			return
+		}
+	}
+	var scheduler *MLFQ
+	switch {
+	case resumed != nil:
+		scheduler = resumed
+		if cfg != nil {
+			if err := scheduler.ApplyConfig(cfg); err != nil {
+				fmt.Printf("Ошибка: %v\n", err)
+				return
+			}
+			break
+		}
+		// Заменяем только явно заданные кванты
+		var quanta []uint
+		if *quantumList != "" {
+			quanta = timeSlices
+		}
+		for i, name := range []string{"q0", "q1", "q2"} {
+			if setFlags[name] && *quantumList == "" && uint(i) < scheduler.NumQueues {
+				if quanta == nil {
+					quanta = append([]uint(nil), scheduler.TimeSlice...)
+				}
+				quanta[i] = timeSlices[i]
+			}
+		}
+		scheduler.SetPolicy(quanta, allotments)
+	case cfg != nil:
+		scheduler = NewMLFQFromConfig(cfg, *ioDuration)
+	default:
+		scheduler = NewMLFQ(*numQueues, timeSlices, allotments, *boost, *ioDuration)
+		scheduler.IOMode = ioMode
+	}
+	if cfg != nil || resumed != nil {
+		// Явно заданные флаги имеют приоритет над файлом
+		if setFlags["B"] {
+			scheduler.BoostTime = *boost
+		}
+		if setFlags["I"] {
+			scheduler.IODuration = *ioDuration
+		}
+		if setFlags["m"] {
+			scheduler.IOMode = ioMode
+		}
+	}
+
+	if resumed == nil {
+		switch *cpuMode {
+		case "shared":
+		case "percpu":
+			scheduler.PerCPU = true
+		default:
+			fmt.Printf("Ошибка: неизвестный режим очередей: %s (доступны: shared, percpu)\n", *cpuMode)
+			return
+		}
+		scheduler.SetCPUs(*cpus)
+		scheduler.Stealing = *stealing
+		scheduler.BalanceInterval = *balanceInterval
+		if *randomIO {
+			scheduler.SetRandomIO(*seed)
+		}
+	} else {
+		if setFlags["S"] {
+			scheduler.Stealing = *stealing
+		}
+		if setFlags["L"] {
+			scheduler.BalanceInterval = *balanceInterval
+		}
+	}
+
+	var policies []comparePolicy
+	if *comparison != "" {
+		if scheduler.CPUs > 1 {
+			fmt.Println("Ошибка: сравнение политик (-p) выполняется только на одном CPU")
+			return
+		}
+		if policies, err = parsePolicies(*comparison, scheduler.TimeSlice[0]); err != nil {
+			fmt.Printf("Ошибка: %v\n", err)
+			return
+		}
+	}
+
+	if *dumpConfig {
+		data, _ := json.MarshalIndent(scheduler.Config(), "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	var tui *TUI
+	switch {
+	case *tuiSpeed > 0:
+		tui = NewTUI(os.Stdout, *tuiSpeed)
+		scheduler.AddObserver(tui)
+		scheduler.MaxStep = 1
+	case !*quiet:
+		printer := NewTextPrinter(os.Stdout)
+		printer.Verbose = *verbose
+		scheduler.AddObserver(printer)
+	}
+	scheduler.StarvationThreshold = *threshold
+	var fairness *FairnessTracker
+	if *window > 0 && (!*quiet || setFlags["W"]) {
+		fairness = NewFairnessTracker(*window)
+		if resumed != nil {
+			fairness.Resume(scheduler)
+		}
+		scheduler.AddObserver(fairness)
+	}
+
+	// Добавляем задачи
+	if resumed != nil {
+		var done, queued int
+		for _, job := range scheduler.Jobs {
+			switch {
+			case job.Done:
+				done++
+			case !job.doingIO:
+				queued++
+			}
+		}
+		fmt.Printf("Продолжение из контрольной точки %s с момента %d: завершено %d, в очередях %d, в I/O %d, не прибыли %d\n",
+			*resumeFile, scheduler.CurrentTime, done, queued, len(scheduler.ioJobs), len(scheduler.PendingJobs))
+	} else if *workload != "" || *workloadFile != "" {
+		for _, job := range jobs {
+			if err := validateHints(job, scheduler.NumQueues); err != nil {
+				fmt.Printf("Ошибка в подсказках: %v\n", err)
+				return
+			}
+		}
 
 		for _, job := range jobs {
 			scheduler.AddPendingJob(job)
-			fmt.Printf("Добавлена задача %d: прибытие=%d, длительность=%d, I/O=%d\n",
-				job.ID, job.ArrivalTime, job.JobLength, job.IOFrequency)
+			if *quiet {
+				continue
+			}
+			fmt.Printf("Добавлена задача %s: прибытие=%d, длительность=%d, I/O=%d",
+				job.Label(), job.ArrivalTime, job.JobLength, job.IOFrequency)
+			if job.IODuration > 0 {
+				fmt.Printf(", длительность I/O=%d", job.IODuration)
+			}
+			if len(job.IOBursts) > 0 {
+				fmt.Printf(", явных операций I/O=%d", len(job.IOBursts))
+			}
+			if hints := hintString(job); hints != "" {
+				fmt.Printf(", подсказки:%s", hints)
+			}
+			fmt.Println()
 		}
 	} else {
 		// Генерируем случайные задачи
-		for i := uint(0); i < *numJobs; i++ {
-			job := &Job{
-				ID:          i + 1,
-				ArrivalTime: uint(rand.Intn(int(*arrivalTime))),
-				JobLength:   uint(rand.Intn(int(*jobLength-10)) + 10), // От 10 до jobLength
-				IOFrequency: *ioFreq,
-				StartTime:   -1,
+		if *arrivalTime == 0 || (*jobLength == 0 && classes == nil) {
+			fmt.Println("Ошибка: для случайных задач нужны -a > 0 и -l > 0")
+			return
+		}
+		rng := rand.New(rand.NewSource(*seed))
+		if classes != nil {
+			jobs = classJobs(rng, classes, *numJobs, *arrivalTime)
+		} else {
+			for i := uint(0); i < *numJobs; i++ {
+				jobs = append(jobs, &Job{
+					ID:          i + 1,
+					ArrivalTime: uint(rng.Intn(int(*arrivalTime))),
+					JobLength:   uint(rng.Intn(int(*jobLength))) + 1, // От 1 до jobLength
+					IOFrequency: *ioFreq,
+					StartTime:   -1,
+				})
 			}
-
+		}
+		for _, job := range jobs {
+			job.Gaming = job.Gaming || gaming[job.ID]
 			scheduler.AddPendingJob(job)
-			fmt.Printf("Добавлена задача %d: прибытие=%d, длительность=%d\n",
-				job.ID, job.ArrivalTime, job.JobLength)
+			if *quiet {
+				continue
+			}
+			fmt.Printf("Добавлена задача %d: прибытие=%d, длительность=%d", job.ID, job.ArrivalTime, job.JobLength)
+			if job.Class != "" {
+				fmt.Printf(", класс=%s", job.Class)
+				if job.IOFrequency > 0 {
+					fmt.Printf(", I/O=%d", job.IOFrequency)
+				}
+				if job.IODuration > 0 {
+					fmt.Printf(", длительность I/O=%d", job.IODuration)
+				}
+			}
+			fmt.Println()
 		}
 	}
 
-	fmt.Printf("\nЗапуск MLFQ планировщика (повышение приоритета каждые %d единиц)\n", *boost)
-	fmt.Printf("Количество очередей: %d\n", *numQueues)
-	fmt.Printf("Временные кванты: %v\n", timeSlices)
-	fmt.Printf("Длительность I/O: %d\n\n", *ioDuration)
+	fmt.Printf("\nЗапуск MLFQ планировщика (повышение приоритета каждые %d единиц)\n", scheduler.BoostTime)
+	fmt.Printf("Количество очередей: %d\n", scheduler.NumQueues)
+	if *configFile != "" {
+		fmt.Printf("Таблица диспетчеризации: %s\n", *configFile)
+		scheduler.printDispatchTable()
+	} else {
+		fmt.Printf("Временные кванты: %v\n", scheduler.TimeSlice)
+		fmt.Printf("Квантов на уровне: %v\n", scheduler.Allotment)
+	}
+	fmt.Printf("Длительность I/O: %d\n", scheduler.IODuration)
+	fmt.Printf("Режим учета I/O: %s\n", scheduler.IOMode)
+	if scheduler.CPUs > 1 {
+		fmt.Printf("Процессоров: %d, очереди: %s", scheduler.CPUs, *cpuMode)
+		if scheduler.PerCPU {
+			fmt.Printf(", перенос простаивающим CPU: %t, выравнивание каждые %d", scheduler.Stealing, scheduler.BalanceInterval)
+		}
+		fmt.Println()
+	}
+	if scheduler.RandomIO {
+		fmt.Printf("Случайный I/O (семя %d)\n", scheduler.randSeed)
+	}
+	if classes != nil {
+		fmt.Println("Классы задач:")
+		for _, class := range classes {
+			fmt.Printf("  %-12s вес %d, длительность %v", class.Name, class.Weight, class.Length)
+			if class.IOFreq.Max > 0 {
+				fmt.Printf(", I/O каждые %v", class.IOFreq)
+				if class.IODuration.Max > 0 {
+					fmt.Printf(" по %v", class.IODuration)
+				}
+			}
+			if class.Gaming {
+				fmt.Printf(", I/O на %.0f%% кванта", gamingShare*100)
+			}
+			fmt.Println()
+		}
+	}
+	fmt.Println()
 
 	// Запускаем планировщик
+	if *checkpointFile != "" {
+		until := *checkpointTime
+		if *maxTime > 0 {
+			until = min(until, *maxTime)
+		}
+		if until > scheduler.CurrentTime {
+			scheduler.Run(until)
+		}
+		if err := scheduler.SaveCheckpoint(*checkpointFile); err != nil {
+			fmt.Printf("Ошибка сохранения контрольной точки: %v\n", err)
+		} else {
+			fmt.Printf("[%d] Контрольная точка сохранена в %s\n", scheduler.CurrentTime, *checkpointFile)
+		}
+	}
 	scheduler.Run(*maxTime)
+	if tui != nil {
+		tui.Close()
+	}
+	scheduler.PrintStatistics()
+	if fairness != nil {
+		fairness.Print(os.Stdout)
+	}
+	if policies != nil {
+		printComparison(comparePolicies(scheduler, jobs, policies, *maxTime))
+	}
 }
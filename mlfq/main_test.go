@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Эталонные нагрузки проверяют совместимость с mlfq.py из OSTEP (уровни
+// пронумерованы наоборот: 0 — наивысший). Режим account соответствует
+// mlfq.py без -S, режим keep — mlfq.py -S. Ожидаемые трассы и времена
+// получены запуском mlfq.py на тех же нагрузках.
+
+// goldenCase — нагрузка и ожидаемый результат
+type goldenCase struct {
+	name       string
+	workload   string // Формат -w
+	quanta     []uint
+	allotments []uint
+	boost      uint
+	ioDuration uint
+	ioMode     IOMode
+
+	trace    string // Задача на каждом такте: "1*10" — задача 1 десять тактов подряд, "-" — простой
+	end      []uint // Время завершения задач
+	response []int  // Время отклика задач
+}
+
+var goldenCases = []goldenCase{
+	{
+		// Рисунок 8.2: одна длинная задача
+		name:       "одна длинная задача",
+		workload:   "0,200",
+		quanta:     []uint{10, 10, 10},
+		ioDuration: 5,
+		ioMode:     IOModeAccount,
+		trace:      "1*200",
+		end:        []uint{200},
+		response:   []int{0},
+	},
+	{
+		// Рисунок 8.3: короткая задача вытесняет длинную
+		name:       "короткая задача",
+		workload:   "0,200;100,20",
+		quanta:     []uint{10, 10, 10},
+		ioDuration: 5,
+		ioMode:     IOModeAccount,
+		trace:      "1*100 2*20 1*100",
+		end:        []uint{220, 120},
+		response:   []int{0, 0},
+	},
+	{
+		// Рисунок 8.4: интерактивная задача отдает процессор после каждого такта
+		name:       "интерактивная задача",
+		workload:   "0,175;50,25,1",
+		quanta:     []uint{10, 10, 10},
+		ioDuration: 5,
+		ioMode:     IOModeAccount,
+		trace:      "1*50" + strings.Repeat(" 2 1*5", 19) + " 2 1*15 2 1*10 2 1*5 2 -*5 2 -*5 2",
+		end:        []uint{197, 210},
+		response:   []int{0, 0},
+	},
+	{
+		// Рисунок 8.5 справа: повышение приоритета каждые 50 единиц
+		name:       "повышение приоритета",
+		workload:   "0,120;100,50,5;100,50,5",
+		quanta:     []uint{10, 10, 10},
+		boost:      50,
+		ioDuration: 5,
+		ioMode:     IOModeKeep,
+		trace:      "1*110" + strings.Repeat(" 2*5 3*5", 4) + " 1*10" + strings.Repeat(" 2*5 3*5", 6),
+		end:        []uint{160, 215, 220},
+		response:   []int{0, 10, 15},
+	},
+	{
+		// Рисунок 8.5 слева: без повышения длинная задача голодает
+		name:       "голодание без повышения",
+		workload:   "0,120;100,50,5;100,50,5",
+		quanta:     []uint{10, 10, 10},
+		ioDuration: 5,
+		ioMode:     IOModeKeep,
+		trace:      "1*100" + strings.Repeat(" 2*5 3*5", 10) + " 1*20",
+		end:        []uint{220, 195, 200},
+		response:   []int{0, 0, 5},
+	},
+	{
+		// Рисунок 8.6 слева: по правилу 4b задача, уходящая в I/O перед
+		// концом кванта, остается на верхнем уровне
+		name:       "обман планировщика в режиме keep",
+		workload:   "0,200;80,100,9",
+		quanta:     []uint{10, 10, 10},
+		ioDuration: 1,
+		ioMode:     IOModeKeep,
+		trace:      "1*80" + strings.Repeat(" 2*9 1", 11) + " 2 1*109",
+		end:        []uint{300, 191},
+		response:   []int{0, 0},
+	},
+	{
+		// Рисунок 8.6 справа: с учетом использованного времени обман не работает
+		name:       "обман планировщика в режиме account",
+		workload:   "0,200;80,100,9",
+		quanta:     []uint{10, 10, 10},
+		ioDuration: 1,
+		ioMode:     IOModeAccount,
+		trace: "1*80 2*9 1 2*9 1 2*2 1*8 2*7 1*10 2*3 1*10 2*6 1*10 2*4 1*10 2*5 1*10 " +
+			"2*5 1*10 2*4 1*10 2*6 1*10 2*3 1*10 2*7 1*10 2*2 1*10 2*9 - 2*9 - 2*9 - 2",
+		end:      []uint{272, 303},
+		response: []int{0, 0},
+	},
+	{
+		name:       "задача длиной 1",
+		workload:   "0,1",
+		quanta:     []uint{10},
+		ioDuration: 5,
+		ioMode:     IOModeAccount,
+		trace:      "1",
+		end:        []uint{1},
+		response:   []int{0},
+	},
+	{
+		// I/O занимает ровно ioDuration тактов
+		name:       "длительность I/O",
+		workload:   "0,4,2",
+		quanta:     []uint{10},
+		ioDuration: 3,
+		ioMode:     IOModeAccount,
+		trace:      "1*2 -*3 1*2",
+		end:        []uint{7},
+		response:   []int{0},
+	},
+	{
+		name:       "разные кванты и лимиты уровней",
+		workload:   "0,30;3,12,4;50,6",
+		quanta:     []uint{2, 4, 8},
+		allotments: []uint{2, 1, 1},
+		ioDuration: 2,
+		ioMode:     IOModeAccount,
+		trace:      "1*4 2*4 1*4 2*4 1*8 2*4 1*14 -*8 3*6",
+		end:        []uint{42, 28, 56},
+		response:   []int{0, 1, 0},
+	},
+	{
+		// Квант истекает одновременно с началом I/O: в режиме keep задача
+		// все равно опускается
+		name:       "истечение кванта при начале I/O",
+		workload:   "0,12,2;0,12",
+		quanta:     []uint{2, 4},
+		ioDuration: 2,
+		ioMode:     IOModeKeep,
+		trace:      "1*2 2*6 1*2 2*4 1*2 2*2 1*2 -*2 1*2 -*2 1*2",
+		end:        []uint{28, 18},
+		response:   []int{0, 2},
+	},
+}
+
+// runGolden выполняет нагрузку и возвращает трассу в формате goldenCase.trace.
+// maxStep ограничивает шаг симуляции (0 — без ограничения).
+func runGolden(t *testing.T, c goldenCase, maxStep uint) (*MLFQ, string) {
+	t.Helper()
+	jobs, err := parseWorkload(c.workload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewMLFQ(uint(len(c.quanta)), c.quanta, c.allotments, c.boost, c.ioDuration)
+	m.IOMode = c.ioMode
+	m.MaxStep = maxStep
+	for _, job := range jobs {
+		m.AddPendingJob(job)
+	}
+
+	var ticks []string
+	m.AddObserver(ObserverFunc(func(m *MLFQ, e Event) {
+		tick := "-"
+		switch e.Type {
+		case EventDispatch:
+			tick = fmt.Sprint(e.JobID)
+		case EventIdle:
+		default:
+			return
+		}
+		for i := uint(0); i < e.Duration; i++ {
+			ticks = append(ticks, tick)
+		}
+	}))
+	m.Run(0)
+	return m, compressTrace(ticks)
+}
+
+// compressTrace сворачивает повторы подряд: 1 1 1 2 -> "1*3 2"
+func compressTrace(ticks []string) string {
+	var runs []string
+	for i := 0; i < len(ticks); {
+		j := i
+		for j < len(ticks) && ticks[j] == ticks[i] {
+			j++
+		}
+		if j-i > 1 {
+			runs = append(runs, fmt.Sprintf("%s*%d", ticks[i], j-i))
+		} else {
+			runs = append(runs, ticks[i])
+		}
+		i = j
+	}
+	return strings.Join(runs, " ")
+}
+
+func TestGoldenTraces(t *testing.T) {
+	for _, c := range goldenCases {
+		t.Run(c.name, func(t *testing.T) {
+			m, trace := runGolden(t, c, 0)
+			if trace != c.trace {
+				t.Errorf("трасса:\n получено %s\n ожидалось %s", trace, c.trace)
+			}
+			jobs := m.SortedJobs()
+			if len(jobs) != len(c.end) {
+				t.Fatalf("задач %d, ожидалось %d", len(jobs), len(c.end))
+			}
+			for i, job := range jobs {
+				if !job.Done {
+					t.Errorf("задача %d не завершена", job.ID)
+					continue
+				}
+				if job.EndTime != c.end[i] {
+					t.Errorf("задача %d: завершение %d, ожидалось %d", job.ID, job.EndTime, c.end[i])
+				}
+				if response := job.StartTime - int(job.ArrivalTime); response != c.response[i] {
+					t.Errorf("задача %d: отклик %d, ожидалось %d", job.ID, response, c.response[i])
+				}
+			}
+		})
+	}
+}
+
+// Пошаговая симуляция должна давать ту же трассу, что и с пропуском
+// промежутков без событий
+func TestGoldenTracesStepByStep(t *testing.T) {
+	for _, c := range goldenCases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, trace := runGolden(t, c, 1); trace != c.trace {
+				t.Errorf("трасса:\n получено %s\n ожидалось %s", trace, c.trace)
+			}
+		})
+	}
+}
+
+func TestRandomJobLengths(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var lengths []uint
+	for _, job := range randomWorkload(rng, 1000, 100, 5, 0) {
+		lengths = append(lengths, job.JobLength)
+	}
+	for length := uint(1); length <= 5; length++ {
+		if !slices.Contains(lengths, length) {
+			t.Errorf("нет задач длиной %d", length)
+		}
+	}
+	if slices.Max(lengths) > 5 {
+		t.Errorf("длина %d больше -l 5", slices.Max(lengths))
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Многопроцессорный MLFQ.
+//
+// В режиме общей структуры (PerCPU == false) все CPU выбирают задачи из одних
+// и тех же очередей: на каждом такте выполняются первые CPUs задач в порядке
+// приоритета. Задача по возможности остается на CPU, где выполнялась раньше.
+//
+// В режиме очередей на каждом CPU (PerCPU == true) задача закреплена за CPU
+// (Job.CPU), и очереди CPU — это задачи общей структуры, закрепленные за ним,
+// в том же порядке. Повышение приоритета происходит одновременно на всех CPU.
+// Прибывшая задача закрепляется за наименее загруженным CPU, после I/O
+// возвращается на свой. Нагрузка выравнивается переносом задач: простаивающий
+// CPU забирает задачу у самого загруженного (Stealing) и/или каждые
+// BalanceInterval единиц задачи переносятся, пока загрузка CPU различается
+// больше чем на одну задачу.
+
+// SetCPUs задает количество процессоров
+func (m *MLFQ) SetCPUs(n uint) {
+	if n == 0 {
+		n = 1
+	}
+	m.CPUs = n
+	m.CPUBusy = make([]uint, n)
+}
+
+// runnable возвращает готовые задачи в порядке приоритета
+func (m *MLFQ) runnable() []*Job {
+	var jobs []*Job
+	for _, queue := range m.Queues {
+		for job := queue.Front(); job != nil; job = job.next {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// dispatch выбирает задачу для каждого CPU (nil — CPU простаивает)
+func (m *MLFQ) dispatch() []*Job {
+	running := make([]*Job, m.CPUs)
+	if m.CPUs == 1 && !m.PerCPU {
+		running[0] = m.GetNextJob()
+		return running
+	}
+
+	if m.PerCPU {
+		if m.Stealing {
+			m.steal()
+		}
+		for _, job := range m.runnable() {
+			if running[job.CPU] == nil {
+				running[job.CPU] = job
+			}
+		}
+		return running
+	}
+
+	// Общая структура: первые CPUs задач, сначала на свои прежние CPU
+	jobs := m.runnable()
+	if uint(len(jobs)) > m.CPUs {
+		jobs = jobs[:m.CPUs]
+	}
+	var rest []*Job
+	for _, job := range jobs {
+		if job.LastCPU >= 0 && running[job.LastCPU] == nil {
+			running[job.LastCPU] = job
+		} else {
+			rest = append(rest, job)
+		}
+	}
+	for cpu := range running {
+		if running[cpu] == nil && len(rest) > 0 {
+			running[cpu], rest = rest[0], rest[1:]
+		}
+	}
+	return running
+}
+
+// cpuLoad возвращает количество готовых задач, закрепленных за каждым CPU
+func (m *MLFQ) cpuLoad() []int {
+	load := make([]int, m.CPUs)
+	for _, job := range m.runnable() {
+		load[job.CPU]++
+	}
+	return load
+}
+
+// placeJob закрепляет прибывшую задачу за наименее загруженным CPU
+func (m *MLFQ) placeJob(job *Job) {
+	if !m.PerCPU {
+		return
+	}
+	load := m.cpuLoad()
+	best := 0
+	for cpu := range load {
+		if load[cpu] < load[best] {
+			best = cpu
+		}
+	}
+	job.CPU = best
+}
+
+// migrate переносит задачу на другой CPU
+func (m *MLFQ) migrate(job *Job, cpu int) {
+	from := job.CPU
+	job.CPU = cpu
+	m.emit(Event{Type: EventMigrate, Time: m.CurrentTime, JobID: job.ID, From: uint(from), To: uint(cpu)})
+}
+
+// lowestPriorityJob возвращает готовую задачу CPU с самым низким приоритетом
+// (последнюю в порядке выбора), не считая той, что будет выполняться первой
+func (m *MLFQ) lowestPriorityJob(cpu int) *Job {
+	var candidates []*Job
+	for _, job := range m.runnable() {
+		if job.CPU == cpu {
+			candidates = append(candidates, job)
+		}
+	}
+	if len(candidates) < 2 {
+		return nil
+	}
+	return candidates[len(candidates)-1]
+}
+
+// steal: каждый CPU без готовых задач забирает задачу у самого загруженного CPU
+func (m *MLFQ) steal() {
+	load := m.cpuLoad()
+	for cpu := range load {
+		if load[cpu] > 0 {
+			continue
+		}
+		victim := 0
+		for v := range load {
+			if load[v] > load[victim] {
+				victim = v
+			}
+		}
+		job := m.lowestPriorityJob(victim)
+		if job == nil {
+			return
+		}
+		m.migrate(job, cpu)
+		load[victim]--
+		load[cpu]++
+	}
+}
+
+// balance переносит задачи с самого загруженного CPU на наименее загруженный,
+// пока разница больше одной задачи
+func (m *MLFQ) balance() {
+	for {
+		load := m.cpuLoad()
+		busiest, idlest := 0, 0
+		for cpu := range load {
+			if load[cpu] > load[busiest] {
+				busiest = cpu
+			}
+			if load[cpu] < load[idlest] {
+				idlest = cpu
+			}
+		}
+		if load[busiest]-load[idlest] <= 1 {
+			return
+		}
+		job := m.lowestPriorityJob(busiest)
+		if job == nil {
+			return
+		}
+		m.migrate(job, idlest)
+	}
+}
+
+// printCPUStats выводит загрузку процессоров и миграции задач
+func (m *MLFQ) printCPUStats() {
+	if m.CPUs < 2 {
+		return
+	}
+
+	mode := "общие очереди"
+	if m.PerCPU {
+		mode = "очереди на каждом CPU"
+	}
+	fmt.Printf("\n=== Процессоры (%d, %s) ===\n", m.CPUs, mode)
+	fmt.Printf("%-4s %-8s %s\n", "CPU", "Занят", "Загрузка")
+	for cpu, busy := range m.CPUBusy {
+		utilization := 0.0
+		if m.CurrentTime > 0 {
+			utilization = 100 * float64(busy) / float64(m.CurrentTime)
+		}
+		fmt.Printf("%-4d %-8d %.1f%%\n", cpu, busy, utilization)
+	}
+
+	fmt.Printf("\nМиграций: %d\n", m.Migrations)
+	jobs := m.SortedJobs()
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].Migrations > jobs[j].Migrations })
+	for _, job := range jobs {
+		if job.Migrations == 0 {
+			break
+		}
+		fmt.Printf("Задача %d: %d\n", job.ID, job.Migrations)
+	}
+}
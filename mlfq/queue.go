@@ -0,0 +1,252 @@
+package main
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// Очереди и календарь событий планировщика.
+//
+// Очередь уровня — двусвязный список задач (поля prev и next задачи), поэтому
+// задача удаляется из середины очереди за O(1). Задачи, ожидающие прибытия,
+// хранятся в порядке времени прибытия, задачи в I/O — в куче по времени
+// возврата в очередь. Вместе с моментом следующего повышения приоритета это
+// календарь, по которому Run пропускает промежутки, в которых ничего не
+// происходит.
+//
+// Время на уровнях, ожидание и перерывы без CPU считаются не на каждом такте,
+// а при выходе задачи из очереди и при ее запуске (settle и endWait).
+
+// JobQueue — очередь задач одного уровня
+type JobQueue struct {
+	head, tail *Job
+	len        int
+}
+
+// PushBack добавляет задачу в конец очереди
+func (q *JobQueue) PushBack(job *Job) {
+	job.prev, job.next = q.tail, nil
+	if q.tail != nil {
+		q.tail.next = job
+	} else {
+		q.head = job
+	}
+	q.tail = job
+	q.len++
+}
+
+// Remove удаляет задачу из очереди
+func (q *JobQueue) Remove(job *Job) {
+	if job.prev != nil {
+		job.prev.next = job.next
+	} else {
+		q.head = job.next
+	}
+	if job.next != nil {
+		job.next.prev = job.prev
+	} else {
+		q.tail = job.prev
+	}
+	job.prev, job.next = nil, nil
+	q.len--
+}
+
+// Front возвращает первую задачу очереди (nil, если очередь пуста)
+func (q *JobQueue) Front() *Job {
+	return q.head
+}
+
+// Len возвращает количество задач в очереди
+func (q *JobQueue) Len() int {
+	return q.len
+}
+
+// IDs возвращает ID задач в порядке очереди
+func (q *JobQueue) IDs() []uint {
+	ids := make([]uint, 0, q.len)
+	for job := q.head; job != nil; job = job.next {
+		ids = append(ids, job.ID)
+	}
+	return ids
+}
+
+// enqueue ставит задачу в конец очереди ее текущего уровня
+func (m *MLFQ) enqueue(job *Job) {
+	m.Queues[job.CurrentQueue].PushBack(job)
+	job.queuedAt = m.CurrentTime
+	job.ranInQueue = 0
+}
+
+// removeFromQueue удаляет задачу из очереди ее текущего уровня
+func (m *MLFQ) removeFromQueue(job *Job) {
+	m.settle(job)
+	m.Queues[job.CurrentQueue].Remove(job)
+}
+
+// settle учитывает время, проведенное задачей в очереди с момента постановки
+// (или предыдущего учета) до текущего момента
+func (m *MLFQ) settle(job *Job) {
+	spent := m.CurrentTime - job.queuedAt
+	job.QueueTime[job.CurrentQueue] += spent
+	job.TotalWait += spent - job.ranInQueue
+	job.queuedAt = m.CurrentTime
+	job.ranInQueue = 0
+}
+
+// settleAll учитывает время всех задач в очередях к концу симуляции
+func (m *MLFQ) settleAll() {
+	for _, queue := range m.Queues {
+		for job := queue.Front(); job != nil; job = job.next {
+			m.settle(job)
+			endWait(job, m.CurrentTime)
+		}
+	}
+}
+
+// ioCalendar — задачи в I/O, упорядоченные по моменту возврата в очередь,
+// а при равных моментах — по порядку начала I/O
+type ioCalendar []*Job
+
+func (c ioCalendar) Len() int { return len(c) }
+
+func (c ioCalendar) Less(i, j int) bool {
+	if c[i].ioReady != c[j].ioReady {
+		return c[i].ioReady < c[j].ioReady
+	}
+	return c[i].ioSeq < c[j].ioSeq
+}
+
+func (c ioCalendar) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+
+func (c *ioCalendar) Push(x any) { *c = append(*c, x.(*Job)) }
+
+func (c *ioCalendar) Pop() any {
+	old := *c
+	job := old[len(old)-1]
+	old[len(old)-1] = nil
+	*c = old[:len(old)-1]
+	return job
+}
+
+// scheduleIO добавляет задачу в календарь I/O. Завершение I/O проверяется
+// в начале такта, поэтому задача возвращается не раньше следующего такта.
+func (m *MLFQ) scheduleIO(job *Job) {
+	job.ioReady = max(job.IOEndTime, m.CurrentTime)
+	job.ioSeq = m.ioSeq
+	job.doingIO = true
+	m.ioSeq++
+	heap.Push(&m.ioJobs, job)
+}
+
+// nextIOComplete возвращает задачу, завершающую I/O к текущему моменту
+// (nil, если таких нет)
+func (m *MLFQ) nextIOComplete() *Job {
+	if len(m.ioJobs) == 0 || m.ioJobs[0].ioReady > m.CurrentTime {
+		return nil
+	}
+	job := heap.Pop(&m.ioJobs).(*Job)
+	job.doingIO = false
+	return job
+}
+
+// IOJobs возвращает задачи в I/O в порядке начала операций
+func (m *MLFQ) IOJobs() []*Job {
+	jobs := append([]*Job(nil), m.ioJobs...)
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ioSeq < jobs[j].ioSeq })
+	return jobs
+}
+
+// nextArrival возвращает задачу, прибывающую к текущему моменту (nil, если
+// таких нет). Задачи с одинаковым временем прибытия идут в порядке добавления.
+func (m *MLFQ) nextArrival() *Job {
+	m.sortPending()
+	if len(m.PendingJobs) == 0 || m.PendingJobs[0].ArrivalTime > m.CurrentTime {
+		return nil
+	}
+	job := m.PendingJobs[0]
+	m.PendingJobs[0] = nil
+	m.PendingJobs = m.PendingJobs[1:]
+	return job
+}
+
+// sortPending упорядочивает ожидающие задачи по времени прибытия, если
+// после последней сортировки задачи добавлялись не по порядку
+func (m *MLFQ) sortPending() {
+	if !m.pendingUnsorted {
+		return
+	}
+	sort.SliceStable(m.PendingJobs, func(i, j int) bool {
+		return m.PendingJobs[i].ArrivalTime < m.PendingJobs[j].ArrivalTime
+	})
+	m.pendingUnsorted = false
+}
+
+// nextEvent возвращает ближайший момент после текущего, когда может
+// измениться состояние очередей: прибытие задачи, завершение I/O или
+// повышение приоритета (ok == false, если событий больше нет)
+func (m *MLFQ) nextEvent() (t uint, ok bool) {
+	consider := func(at uint) {
+		if !ok || at < t {
+			t, ok = at, true
+		}
+	}
+	m.sortPending()
+	if len(m.PendingJobs) > 0 {
+		consider(m.PendingJobs[0].ArrivalTime)
+	}
+	if len(m.ioJobs) > 0 {
+		consider(m.ioJobs[0].ioReady)
+	}
+	if m.BoostTime > 0 {
+		consider(m.LastBoost + m.BoostTime)
+	}
+	return t, ok
+}
+
+// stepLength возвращает, на сколько единиц можно продвинуть симуляцию, не
+// пропустив ни одного решения планировщика: до ближайшего события календаря,
+// maxTime и MaxStep, а если процессор занят задачей job — также до конца ее
+// кванта, завершения или I/O
+func (m *MLFQ) stepLength(job *Job, maxTime uint) uint {
+	limit := uint(math.MaxUint)
+	if next, ok := m.nextEvent(); ok {
+		limit = next - m.CurrentTime
+	}
+	if maxTime > 0 {
+		limit = min(limit, maxTime-m.CurrentTime)
+	}
+	if m.MaxStep > 0 {
+		limit = min(limit, m.MaxStep)
+	}
+	if job == nil {
+		if limit == math.MaxUint {
+			return 1
+		}
+		return limit
+	}
+
+	d := min(job.TimeLeft, job.TimeSliceLeft, limit)
+	if job.nextBurst < len(job.IOBursts) {
+		d = min(d, job.IOBursts[job.nextBurst].At-(job.JobLength-job.TimeLeft))
+	}
+	if job.IOFrequency > 0 {
+		if m.RandomIO {
+			d = min(d, max(job.IOBurstLeft, 1))
+		} else {
+			executed := job.JobLength - job.TimeLeft
+			d = min(d, job.IOFrequency-executed%job.IOFrequency)
+		}
+	}
+	if job.Gaming {
+		slice := m.TimeSlice[job.CurrentQueue]
+		used := slice - job.TimeSliceLeft
+		threshold := max(uint(float64(slice)*gamingShare), 1)
+		if threshold > used {
+			d = min(d, threshold-used)
+		} else {
+			d = 1
+		}
+	}
+	return max(d, 1)
+}
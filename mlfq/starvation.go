@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// endWait завершает в момент t перерыв задачи (время в очереди без
+// процессора), начавшийся в readySince, и запоминает самый длинный перерыв
+func endWait(job *Job, t uint) {
+	if streak := t - job.readySince; streak > job.MaxWaitStreak {
+		job.MaxWaitStreak = streak
+		job.MaxWaitStart = job.readySince
+	}
+}
+
+// printStarvation выводит самый длинный перерыв каждой задачи и задачи,
+// превысившие порог StarvationThreshold
+func (m *MLFQ) printStarvation() {
+	jobs := m.SortedJobs()
+	if len(jobs) == 0 {
+		return
+	}
+
+	fmt.Println("\n=== Голодание ===")
+	fmt.Printf("%-6s %-16s %s\n", "Задача", "Макс. без CPU", "Интервал")
+	var worst *Job
+	var starving []*Job
+	for _, job := range jobs {
+		interval := "-"
+		if job.MaxWaitStreak > 0 {
+			interval = fmt.Sprintf("[%d, %d)", job.MaxWaitStart, job.MaxWaitStart+job.MaxWaitStreak)
+		}
+		fmt.Printf("%-6d %-16d %s\n", job.ID, job.MaxWaitStreak, interval)
+		if worst == nil || job.MaxWaitStreak > worst.MaxWaitStreak {
+			worst = job
+		}
+		if m.StarvationThreshold > 0 && job.MaxWaitStreak > m.StarvationThreshold {
+			starving = append(starving, job)
+		}
+	}
+	fmt.Printf("\nНаибольший перерыв: %d (задача %d), период повышения приоритета: %d\n",
+		worst.MaxWaitStreak, worst.ID, m.BoostTime)
+
+	if m.StarvationThreshold > 0 {
+		if len(starving) == 0 {
+			fmt.Printf("Нет задач, ожидавших процессор дольше %d\n", m.StarvationThreshold)
+		}
+		for _, job := range starving {
+			fmt.Printf("ГОЛОДАНИЕ: задача %d ждала процессор %d единиц подряд (порог %d)\n",
+				job.ID, job.MaxWaitStreak, m.StarvationThreshold)
+		}
+	}
+}
+
+// span — интервал тактов [start, end)
+type span struct {
+	start, end uint
+}
+
+// addSpan добавляет интервал в конец упорядоченного списка, объединяя его с
+// предыдущим, если они соприкасаются
+func addSpan(spans []span, start, end uint) []span {
+	if start >= end {
+		return spans
+	}
+	if n := len(spans); n > 0 && spans[n-1].end >= start {
+		spans[n-1].end = max(spans[n-1].end, end)
+		return spans
+	}
+	return append(spans, span{start, end})
+}
+
+// jobTimeline — интервалы, когда задача была в очереди и когда выполнялась
+type jobTimeline struct {
+	arrival   uint
+	runnable  []span // Задача была в очереди (не в I/O и не завершена)
+	ran       []span // Задача выполнялась
+	ready     bool   // Задача сейчас в очереди
+	readyFrom uint   // Начало текущего интервала в очереди
+}
+
+// setReady отмечает, что с момента t задача в очереди или вне ее
+func (j *jobTimeline) setReady(ready bool, t uint) {
+	switch {
+	case ready && !j.ready:
+		j.readyFrom = t
+	case !ready && j.ready:
+		j.runnable = addSpan(j.runnable, j.readyFrom, t)
+	}
+	j.ready = ready
+}
+
+// FairnessTracker — наблюдатель, который восстанавливает по событиям
+// планировщика, когда каждая задача была готова к выполнению и когда
+// выполнялась, и считает долю CPU в скользящих окнах. Состояние задачи
+// хранится интервалами, поэтому затраты пропорциональны количеству
+// событий, а не тактов.
+type FairnessTracker struct {
+	Window uint // Размер окна
+
+	jobs  map[uint]*jobTimeline
+	order []uint
+	end   uint // Конец последнего такта, отмеченного DISPATCH или IDLE
+}
+
+// NewFairnessTracker создает наблюдатель с окном window
+func NewFairnessTracker(window uint) *FairnessTracker {
+	return &FairnessTracker{
+		Window: window,
+		jobs:   make(map[uint]*jobTimeline),
+	}
+}
+
+// Resume начинает наблюдение с текущего состояния планировщика (например,
+// продолженного из контрольной точки): прибывшие незавершенные задачи
+// учитываются с текущего момента
+func (f *FairnessTracker) Resume(m *MLFQ) {
+	for _, job := range m.SortedJobs() {
+		if job.Done {
+			continue
+		}
+		timeline := &jobTimeline{arrival: m.CurrentTime}
+		timeline.setReady(!job.doingIO, m.CurrentTime)
+		f.jobs[job.ID] = timeline
+		f.order = append(f.order, job.ID)
+	}
+}
+
+// OnEvent обновляет состояние задач. События, меняющие очередь, приходят до
+// первого DISPATCH или IDLE своего момента, поэтому задача считается в
+// очереди с такта события.
+func (f *FairnessTracker) OnEvent(m *MLFQ, e Event) {
+	switch e.Type {
+	case EventArrival:
+		timeline := &jobTimeline{arrival: e.Time}
+		timeline.setReady(true, e.Time)
+		f.jobs[e.JobID] = timeline
+		f.order = append(f.order, e.JobID)
+	case EventIOComplete:
+		if job := f.jobs[e.JobID]; job != nil {
+			job.setReady(true, e.Time)
+		}
+	case EventIOStart, EventFinish:
+		if job := f.jobs[e.JobID]; job != nil {
+			job.setReady(false, e.Time)
+		}
+	case EventDispatch, EventIdle:
+		end := e.Time + max(e.Duration, 1)
+		f.end = max(f.end, end)
+		if job := f.jobs[e.JobID]; e.Type == EventDispatch && job != nil {
+			job.ran = addSpan(job.ran, e.Time, end)
+		}
+	}
+}
+
+// WindowShare — доля CPU задачи в окнах, где она была готова к выполнению
+type WindowShare struct {
+	JobID   uint
+	Windows int     // Количество окон, в которых задача хотя бы раз была в очереди
+	Min     float64 // Наименьшая доля
+	Mean    float64 // Средняя доля
+	Zero    int     // Окон, в которых задача ждала, но не получила процессор
+}
+
+// Shares вычисляет долю CPU каждой задачи в скользящих окнах размера Window.
+// Окна начинаются в каждом такте с прибытия задачи до последнего такта
+// симуляции.
+func (f *FairnessTracker) Shares() []WindowShare {
+	var shares []WindowShare
+	ids := append([]uint(nil), f.order...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		shares = append(shares, f.jobShare(id))
+	}
+	return shares
+}
+
+// jobShare вычисляет долю CPU задачи в окнах. Количество тактов выполнения
+// в окне [s, s+w) меняется с s линейно между точками, где s или s+w
+// пересекает границу интервала выполнения, поэтому окна обходятся
+// отрезками между такими точками, а не по одному.
+func (f *FairnessTracker) jobShare(id uint) WindowShare {
+	job := f.jobs[id]
+	share := WindowShare{JobID: id, Min: 1}
+	w := int(f.Window)
+	length := 0 // Тактов с прибытия задачи
+	if f.end > job.arrival {
+		length = int(f.end - job.arrival)
+	}
+	if w == 0 || length < w {
+		share.Min = 0
+		return share
+	}
+
+	// Интервалы относительно прибытия
+	relative := func(spans []span) [][2]int {
+		result := make([][2]int, 0, len(spans))
+		for _, s := range spans {
+			result = append(result, [2]int{int(s.start - job.arrival), int(s.end - job.arrival)})
+		}
+		return result
+	}
+	runnable := job.runnable
+	if job.ready {
+		runnable = addSpan(append([]span(nil), runnable...), job.readyFrom, f.end)
+	}
+	ran := relative(job.ran)
+
+	// before[i] — тактов выполнения в ran[:i]
+	before := make([]int, len(ran)+1)
+	for i, r := range ran {
+		before[i+1] = before[i] + r[1] - r[0]
+	}
+	// ranBefore возвращает количество тактов выполнения в [0, x) и
+	// выполнялась ли задача в такте x
+	ranBefore := func(x int) (int, int) {
+		i := sort.Search(len(ran), func(i int) bool { return ran[i][1] > x })
+		if i < len(ran) && ran[i][0] <= x {
+			return before[i] + x - ran[i][0], 1
+		}
+		return before[i], 0
+	}
+
+	// Точки, где меняется наклон
+	var breaks []int
+	for _, r := range ran {
+		breaks = append(breaks, r[0], r[1], r[0]-w, r[1]-w)
+	}
+	sort.Ints(breaks)
+
+	total := 0 // Тактов выполнения во всех окнах
+	// segment учитывает окна с началом в [s, next), где наклон постоянный
+	segment := func(s, next int) {
+		n := next - s
+		low, ranLow := ranBefore(s)
+		high, ranHigh := ranBefore(s + w)
+		r, k := high-low, ranHigh-ranLow // Тактов выполнения в первом окне и шаг
+		share.Windows += n
+		total += n*r + k*n*(n-1)/2
+		last := r + k*(n-1)
+		share.Min = min(share.Min, float64(min(r, last))/float64(w))
+		if r == 0 && k == 0 {
+			share.Zero += n
+		} else if r == 0 || last == 0 {
+			share.Zero++
+		}
+	}
+
+	// Окно [s, s+w) учитывается, если задача была в очереди хотя бы в одном
+	// его такте: s из [a-w+1, b-1] для интервала [a, b)
+	from := 0
+	for _, r := range relative(runnable) {
+		lo, hi := max(r[0]-w+1, from), min(r[1]-1, length-w)
+		if lo > hi {
+			continue
+		}
+		i := sort.SearchInts(breaks, lo+1)
+		for s := lo; s <= hi; {
+			next := hi + 1
+			if i < len(breaks) && breaks[i] < next {
+				next = breaks[i]
+			}
+			segment(s, next)
+			s = next
+			for i < len(breaks) && breaks[i] <= s {
+				i++
+			}
+		}
+		from = hi + 1
+	}
+
+	if share.Windows == 0 {
+		share.Min = 0
+	} else {
+		share.Mean = float64(total) / float64(share.Windows*w)
+	}
+	return share
+}
+
+// Print выводит долю CPU задач в скользящих окнах
+func (f *FairnessTracker) Print(w io.Writer) {
+	fmt.Fprintf(w, "\n=== Доля CPU в скользящих окнах по %d ===\n", f.Window)
+	fmt.Fprintf(w, "%-6s %-6s %-10s %-10s %s\n", "Задача", "Окон", "Мин. доля", "Средняя", "Без CPU")
+	for _, s := range f.Shares() {
+		if s.Windows == 0 {
+			fmt.Fprintf(w, "%-6d %-6d %-10s %-10s %s\n", s.JobID, 0, "-", "-", "-")
+			continue
+		}
+		fmt.Fprintf(w, "%-6d %-6d %-10.2f %-10.2f %d\n", s.JobID, s.Windows, s.Min, s.Mean, s.Zero)
+	}
+	fmt.Fprintln(w, "Учитываются окна, в которых задача хотя бы раз была в очереди")
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Управляющие последовательности ANSI
+const (
+	ansiAltScreen  = "\033[?1049h"
+	ansiMainScreen = "\033[?1049l"
+	ansiHideCursor = "\033[?25l"
+	ansiShowCursor = "\033[?25h"
+	ansiHome       = "\033[H"
+	ansiClearLine  = "\033[K"
+	ansiClearBelow = "\033[J"
+	ansiReset      = "\033[0m"
+	ansiReverse    = "\033[7m"
+	ansiDim        = "\033[2m"
+)
+
+// tuiMaxSpeed и tuiMinSpeed ограничивают скорость воспроизведения (тактов в секунду)
+const (
+	tuiMinSpeed = 1
+	tuiMaxSpeed = 1000
+	tuiBarWidth = 30
+	tuiMaxLane  = 24 // Сколько задач показывать в одной очереди
+)
+
+// TUI — наблюдатель, который перерисовывает экран после выбора задач на
+// каждом такте. Управление с клавиатуры: пробел — пауза, n — шаг при паузе,
+// +/- — скорость, q — выход. Если stdin не терминал, управление отключено.
+type TUI struct {
+	w        io.Writer
+	Speed    int // Тактов в секунду
+	paused   bool
+	keys     chan byte
+	sttyMode string // Исходный режим терминала для восстановления
+	running  map[uint]uint
+	tick     uint
+	started  bool
+
+	interrupts chan os.Signal // Ctrl+C до закрытия TUI
+	done       chan struct{}  // Закрывается в Close
+	closeOnce  sync.Once      // Close вызывают основная горутина и обработчик Ctrl+C
+}
+
+// NewTUI создает TUI, переключает терминал в режим посимвольного ввода и
+// открывает альтернативный экран
+func NewTUI(w io.Writer, speed int) *TUI {
+	t := &TUI{
+		w:          w,
+		Speed:      min(max(speed, tuiMinSpeed), tuiMaxSpeed),
+		running:    make(map[uint]uint),
+		interrupts: make(chan os.Signal, 1),
+		done:       make(chan struct{}),
+	}
+
+	if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		if mode, err := stty("-g"); err == nil {
+			if _, err := stty("cbreak", "-echo"); err == nil {
+				t.sttyMode = strings.TrimSpace(mode)
+				t.keys = make(chan byte, 16)
+				go t.readKeys()
+			}
+		}
+	}
+
+	// Восстанавливаем терминал при Ctrl+C; после Close сигнал снова
+	// обрабатывается по умолчанию
+	signal.Notify(t.interrupts, os.Interrupt)
+	go func() {
+		select {
+		case <-t.interrupts:
+			t.Close()
+			os.Exit(1)
+		case <-t.done:
+		}
+	}()
+
+	fmt.Fprint(t.w, ansiAltScreen+ansiHideCursor)
+	return t
+}
+
+// stty выполняет stty для терминала stdin
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// readKeys передает нажатые клавиши в канал
+func (t *TUI) readKeys() {
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		if n == 1 {
+			t.keys <- buf[0]
+		}
+	}
+}
+
+// Close восстанавливает терминал и перестает перехватывать Ctrl+C.
+// Повторные и одновременные вызовы ничего не делают.
+func (t *TUI) Close() {
+	t.closeOnce.Do(func() {
+		signal.Stop(t.interrupts)
+		close(t.done)
+		fmt.Fprint(t.w, ansiShowCursor+ansiMainScreen)
+		if t.sttyMode != "" {
+			stty(t.sttyMode)
+		}
+	})
+}
+
+// OnEvent запоминает выполняемые задачи и после выбора задачи последним CPU
+// перерисовывает экран
+func (t *TUI) OnEvent(m *MLFQ, e Event) {
+	if e.Type != EventDispatch && e.Type != EventIdle {
+		return
+	}
+	if !t.started || e.Time != t.tick {
+		t.started = true
+		t.tick = e.Time
+		clear(t.running)
+	}
+	if e.Type == EventDispatch {
+		t.running[e.CPU] = e.JobID
+	}
+	if e.CPU+1 < m.CPUs {
+		return
+	}
+
+	t.render(m)
+	t.wait(m)
+}
+
+// wait выдерживает паузу между тактами и обрабатывает клавиши
+func (t *TUI) wait(m *MLFQ) {
+	for {
+		var timeout <-chan time.Time
+		if !t.paused {
+			timeout = time.After(time.Second / time.Duration(t.Speed))
+		}
+		select {
+		case <-timeout:
+			return
+		case key := <-t.keys:
+			switch key {
+			case ' ', 'p':
+				t.paused = !t.paused
+			case 'n', 's':
+				if t.paused {
+					return
+				}
+			case '+', '=':
+				t.Speed = min(t.Speed*2, tuiMaxSpeed)
+			case '-', '_':
+				t.Speed = max(t.Speed/2, tuiMinSpeed)
+			case 'q', 3: // 3 — Ctrl+C при отключенной обработке сигналов
+				t.paused = false
+				m.Stop()
+				return
+			}
+			t.render(m)
+		}
+	}
+}
+
+// jobColor возвращает цвет задачи
+func jobColor(id uint) string {
+	return fmt.Sprintf("\033[%dm", 31+(id-1)%6)
+}
+
+// token возвращает обозначение задачи; выполняемые задачи выделяются инверсией
+func (t *TUI) token(id uint) string {
+	for _, running := range t.running {
+		if running == id {
+			return ansiReverse + jobColor(id) + fmt.Sprintf(" J%d ", id) + ansiReset
+		}
+	}
+	return jobColor(id) + fmt.Sprintf(" J%d ", id) + ansiReset
+}
+
+// render перерисовывает экран
+func (t *TUI) render(m *MLFQ) {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&b, format, args...)
+		b.WriteString(ansiClearLine + "\n")
+	}
+
+	b.WriteString(ansiHome)
+	status := fmt.Sprintf("скорость %d такт/с", t.Speed)
+	if t.paused {
+		status = "ПАУЗА"
+	}
+	boost := "повышение отключено"
+	if m.BoostTime > 0 {
+		boost = fmt.Sprintf("до повышения: %d", m.LastBoost+m.BoostTime-m.CurrentTime)
+	}
+	line("MLFQ  время %d  |  %s  |  %s", m.CurrentTime, boost, status)
+	line("")
+
+	// Выполняемые задачи
+	var cpus []string
+	for cpu := uint(0); cpu < m.CPUs; cpu++ {
+		if id, ok := t.running[cpu]; ok {
+			cpus = append(cpus, fmt.Sprintf("CPU%d:%s", cpu, t.token(id)))
+		} else {
+			cpus = append(cpus, fmt.Sprintf("CPU%d: %s-%s ", cpu, ansiDim, ansiReset))
+		}
+	}
+	line("%s", strings.Join(cpus, "  "))
+	line("")
+
+	// Очереди
+	for level, queue := range m.Queues {
+		var tokens []string
+		for i, id := range queue.IDs() {
+			if i == tuiMaxLane {
+				tokens = append(tokens, fmt.Sprintf(" … +%d", queue.Len()-i))
+				break
+			}
+			tokens = append(tokens, t.token(id))
+		}
+		line("Q%-2d квант %-4d │%s", level, m.TimeSlice[level], strings.Join(tokens, ""))
+	}
+
+	// I/O
+	var waiting []string
+	for _, job := range m.IOJobs() {
+		waiting = append(waiting, fmt.Sprintf("%sJ%d%s (до %d)", jobColor(job.ID), job.ID, ansiReset, job.IOEndTime))
+	}
+	line("I/O            │ %s", strings.Join(waiting, "  "))
+	line("")
+
+	// Прогресс задач
+	jobs := m.SortedJobs()
+	jobs = append(jobs, m.PendingJobs...)
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	for _, job := range jobs {
+		done := job.JobLength - job.TimeLeft
+		filled := 0
+		if job.JobLength > 0 {
+			filled = int(done * tuiBarWidth / job.JobLength)
+		}
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", tuiBarWidth-filled)
+
+		state := fmt.Sprintf("Q%d", job.CurrentQueue)
+		switch {
+		case job.Done:
+			state = fmt.Sprintf("завершена в %d", job.EndTime)
+		case m.Jobs[job.ID] == nil:
+			state = fmt.Sprintf("прибудет в %d", job.ArrivalTime)
+		case m.inIO(job.ID):
+			state = "I/O"
+		}
+		line("%sJ%-3d%s %s %3d/%-3d %s", jobColor(job.ID), job.ID, ansiReset, bar, done, job.JobLength, state)
+	}
+
+	line("")
+	if t.keys != nil {
+		line("%sпробел — пауза, n — шаг, +/- — скорость, q — выход%s", ansiDim, ansiReset)
+	}
+	b.WriteString(ansiClearBelow)
+	io.WriteString(t.w, b.String())
+}
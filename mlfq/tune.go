@@ -0,0 +1,396 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TuneParams — одна проверяемая конфигурация планировщика
+type TuneParams struct {
+	Queues uint   `json:"queues"`
+	Quanta []uint `json:"quanta"`
+	Boost  uint   `json:"boost"`
+}
+
+// TuneScore — значения всех целевых функций, усредненные по набору нагрузок
+type TuneScore struct {
+	Response   float64 `json:"response"`   // Среднее время отклика интерактивных задач
+	Turnaround float64 `json:"turnaround"` // Среднее оборотное время пакетных задач
+	Starvation float64 `json:"starvation"` // Наибольший перерыв задачи без CPU
+}
+
+// TuneResult — оценка одной конфигурации
+type TuneResult struct {
+	TuneParams
+	TuneScore
+	Score float64 `json:"score"` // Значение выбранной целевой функции
+}
+
+// TuneConfig задает поиск параметров
+type TuneConfig struct {
+	Mode       string   // grid или random
+	Objective  string   // response, turnaround или starvation
+	Queues     []uint   // Допустимое количество очередей
+	Quanta     []uint   // Допустимые значения квантов
+	Boosts     []uint   // Допустимые периоды повышения приоритета (0 = отключено)
+	Samples    int      // Количество конфигураций для случайного поиска
+	Seed       int64    // Семя случайного поиска
+	Workloads  [][]*Job // Набор нагрузок; задачи копируются для каждого запуска
+	IODuration uint
+	IOMode     IOMode
+	RandomIO   bool // Случайный I/O; у каждой задачи свой генератор, поэтому ее операции I/O одинаковы во всех конфигурациях
+	Workers    int  // Размер пула воркеров (0 = количество CPU)
+}
+
+// tuneMaxConfigs ограничивает размер полного перебора
+const tuneMaxConfigs = 100000
+
+// tuneObjective возвращает значение целевой функции
+func tuneObjective(score TuneScore, objective string) (float64, error) {
+	switch objective {
+	case "response":
+		return score.Response, nil
+	case "turnaround":
+		return score.Turnaround, nil
+	case "starvation":
+		return score.Starvation, nil
+	}
+	return 0, fmt.Errorf("неизвестная целевая функция: %s (доступны: response, turnaround, starvation)", objective)
+}
+
+// interactive проверяет, относится ли задача к интерактивным
+func (j *Job) interactive() bool {
+	return j.IOFrequency > 0 || len(j.IOBursts) > 0 || j.Gaming
+}
+
+// cloneJobs создает копии задач в исходном состоянии
+func cloneJobs(jobs []*Job) []*Job {
+	clones := make([]*Job, len(jobs))
+	for i, job := range jobs {
+		clones[i] = &Job{
+			ID:           job.ID,
+			Name:         job.Name,
+			Class:        job.Class,
+			ArrivalTime:  job.ArrivalTime,
+			JobLength:    job.JobLength,
+			IOFrequency:  job.IOFrequency,
+			IODuration:   job.IODuration,
+			IOBursts:     job.IOBursts,
+			InitialQueue: job.InitialQueue,
+			Ceiling:      job.Ceiling,
+			Floor:        job.Floor,
+			Gaming:       job.Gaming,
+			StartTime:    -1,
+		}
+	}
+	return clones
+}
+
+// evaluate запускает конфигурацию на всех нагрузках без вывода
+func evaluate(params TuneParams, cfg TuneConfig) TuneScore {
+	var score TuneScore
+	for w, workload := range cfg.Workloads {
+		m := NewMLFQ(params.Queues, params.Quanta, nil, params.Boost, cfg.IODuration)
+		m.IOMode = cfg.IOMode
+		if cfg.RandomIO {
+			// Сдвиг на 32 бита: семена задач разных нагрузок не совпадают
+			m.SetRandomIO(cfg.Seed + int64(w)<<32)
+		}
+		for _, job := range cloneJobs(workload) {
+			m.AddPendingJob(job)
+		}
+		m.Run(0)
+
+		var response, turnaround, allResponse, allTurnaround []float64
+		var starvation float64
+		for _, job := range m.SortedJobs() {
+			r := float64(job.StartTime - int(job.ArrivalTime))
+			t := float64(job.EndTime - job.ArrivalTime)
+			if job.interactive() {
+				response = append(response, r)
+			} else {
+				turnaround = append(turnaround, t)
+			}
+			allResponse = append(allResponse, r)
+			allTurnaround = append(allTurnaround, t)
+			starvation = math.Max(starvation, float64(job.MaxWaitStreak))
+		}
+		// Если в нагрузке нет задач нужного класса, учитываются все задачи
+		if len(response) == 0 {
+			response = allResponse
+		}
+		if len(turnaround) == 0 {
+			turnaround = allTurnaround
+		}
+		score.Response += mean(response)
+		score.Turnaround += mean(turnaround)
+		score.Starvation += starvation
+	}
+
+	n := float64(len(cfg.Workloads))
+	score.Response /= n
+	score.Turnaround /= n
+	score.Starvation /= n
+	return score
+}
+
+// mean возвращает среднее значение
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+// quantaSequences возвращает все неубывающие последовательности длины n из
+// значений values: на уровнях с более низким приоритетом квант не меньше
+func quantaSequences(values []uint, n uint) [][]uint {
+	var result [][]uint
+	seq := make([]uint, n)
+	var build func(level uint, from int)
+	build = func(level uint, from int) {
+		if level == n {
+			result = append(result, append([]uint(nil), seq...))
+			return
+		}
+		for i := from; i < len(values); i++ {
+			seq[level] = values[i]
+			build(level+1, i)
+		}
+	}
+	build(0, 0)
+	return result
+}
+
+// uniqueSorted возвращает отсортированные значения без повторов
+func uniqueSorted(values []uint) []uint {
+	sorted := append([]uint(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var result []uint
+	for i, v := range sorted {
+		if i == 0 || v != sorted[i-1] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// tuneCandidates строит список конфигураций для перебора
+func tuneCandidates(cfg TuneConfig) ([]TuneParams, error) {
+	quanta := uniqueSorted(cfg.Quanta)
+	var candidates []TuneParams
+
+	switch cfg.Mode {
+	case "grid":
+		for _, n := range uniqueSorted(cfg.Queues) {
+			seqs := quantaSequences(quanta, n)
+			if len(candidates)+len(seqs)*len(cfg.Boosts) > tuneMaxConfigs {
+				return nil, fmt.Errorf("слишком много конфигураций для полного перебора (больше %d), используйте random", tuneMaxConfigs)
+			}
+			for _, seq := range seqs {
+				for _, boost := range uniqueSorted(cfg.Boosts) {
+					candidates = append(candidates, TuneParams{Queues: n, Quanta: seq, Boost: boost})
+				}
+			}
+		}
+	case "random":
+		rng := rand.New(rand.NewSource(cfg.Seed))
+		seen := make(map[string]bool)
+		// Ограничиваем число попыток, если пространство меньше Samples
+		for attempts := 0; len(candidates) < cfg.Samples && attempts < cfg.Samples*20; attempts++ {
+			n := cfg.Queues[rng.Intn(len(cfg.Queues))]
+			seq := make([]uint, n)
+			for i := range seq {
+				seq[i] = quanta[rng.Intn(len(quanta))]
+			}
+			sort.Slice(seq, func(i, j int) bool { return seq[i] < seq[j] })
+			params := TuneParams{Queues: n, Quanta: seq, Boost: cfg.Boosts[rng.Intn(len(cfg.Boosts))]}
+			key := fmt.Sprint(params)
+			if !seen[key] {
+				seen[key] = true
+				candidates = append(candidates, params)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("неизвестный режим поиска: %s (доступны: grid, random)", cfg.Mode)
+	}
+	return candidates, nil
+}
+
+// Tune оценивает конфигурации в пуле воркеров и возвращает их в порядке
+// возрастания целевой функции. При равенстве выше конфигурация с меньшей
+// суммой остальных показателей, затем — более ранняя в порядке перебора,
+// поэтому результат не зависит от количества воркеров.
+func Tune(cfg TuneConfig) ([]TuneResult, error) {
+	if _, err := tuneObjective(TuneScore{}, cfg.Objective); err != nil {
+		return nil, err
+	}
+	if len(cfg.Queues) == 0 || len(cfg.Quanta) == 0 || len(cfg.Boosts) == 0 || len(cfg.Workloads) == 0 {
+		return nil, fmt.Errorf("пустое пространство поиска или набор нагрузок")
+	}
+	candidates, err := tuneCandidates(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	results := make([]TuneResult, len(candidates))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				score := evaluate(candidates[i], cfg)
+				value, _ := tuneObjective(score, cfg.Objective)
+				results[i] = TuneResult{TuneParams: candidates[i], TuneScore: score, Score: value}
+			}
+		}()
+	}
+	for i := range candidates {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score < b.Score
+		}
+		return a.Response+a.Turnaround+a.Starvation < b.Response+b.Turnaround+b.Starvation
+	})
+	return results, nil
+}
+
+// randomWorkload генерирует случайные задачи: каждая вторая задача
+// интерактивная и выполняет I/O каждые ioFreq единиц
+func randomWorkload(rng *rand.Rand, numJobs, maxArrival, maxLength, ioFreq uint) []*Job {
+	var jobs []*Job
+	for i := uint(0); i < numJobs; i++ {
+		job := &Job{
+			ID:          i + 1,
+			ArrivalTime: uint(rng.Intn(int(maxArrival))),
+			JobLength:   uint(rng.Intn(int(maxLength))) + 1, // От 1 до maxLength
+			StartTime:   -1,
+		}
+		if i%2 == 1 {
+			job.IOFrequency = ioFreq
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// printTuneResults выводит лучшие конфигурации
+func printTuneResults(cfg TuneConfig, results []TuneResult, top int) {
+	fmt.Printf("\n=== Подбор параметров (%s, целевая функция: %s) ===\n", cfg.Mode, cfg.Objective)
+	fmt.Printf("Проверено конфигураций: %d, нагрузок: %d\n\n", len(results), len(cfg.Workloads))
+
+	fmt.Printf("%-5s %-9s %-20s %-9s %-8s %-10s %s\n",
+		"Место", "Очередей", "Кванты", "Повышение", "Отклик", "Оборотное", "Макс. без CPU")
+	fmt.Println(strings.Repeat("-", 80))
+	for i, r := range results {
+		if i == top {
+			break
+		}
+		fmt.Printf("%-5d %-9d %-20s %-9d %-8.2f %-10.2f %.2f\n",
+			i+1, r.Queues, fmt.Sprint(r.Quanta), r.Boost, r.Response, r.Turnaround, r.Starvation)
+	}
+
+	if len(results) > 0 {
+		best := results[0]
+		m := NewMLFQ(best.Queues, best.Quanta, nil, best.Boost, cfg.IODuration)
+		m.IOMode = cfg.IOMode
+		data, _ := json.MarshalIndent(m.Config(), "", "  ")
+		fmt.Printf("\nЛучшая конфигурация (для -c):\n%s\n", data)
+	}
+}
+
+// tuneOptions — параметры командной строки режима подбора
+type tuneOptions struct {
+	mode, objective     string
+	queues, quanta      string
+	boosts              string
+	samples, workloads  int
+	top, workers        int
+	jobs                []*Job     // Заданная нагрузка (nil — случайные)
+	classes             []JobClass // Классы случайных задач (nil — randomWorkload)
+	seed                int64
+	numJobs, maxArrival uint
+	maxLength, ioFreq   uint
+	ioDuration          uint
+	ioMode              IOMode
+	randomIO            bool
+}
+
+// runTune выполняет подбор параметров и выводит результаты
+func runTune(opts tuneOptions) {
+	cfg := TuneConfig{
+		Mode:       opts.mode,
+		Objective:  opts.objective,
+		Samples:    opts.samples,
+		Seed:       opts.seed,
+		IODuration: opts.ioDuration,
+		IOMode:     opts.ioMode,
+		RandomIO:   opts.randomIO,
+		Workers:    opts.workers,
+	}
+
+	var err error
+	if cfg.Queues, err = parseUintList(opts.queues); err != nil {
+		fmt.Printf("Ошибка в списке количества очередей: %v\n", err)
+		return
+	}
+	if cfg.Quanta, err = parseUintList(opts.quanta); err != nil {
+		fmt.Printf("Ошибка в списке квантов: %v\n", err)
+		return
+	}
+	if cfg.Boosts, err = parseUintValues(opts.boosts, 0); err != nil {
+		fmt.Printf("Ошибка в списке периодов повышения: %v\n", err)
+		return
+	}
+
+	if opts.jobs != nil {
+		cfg.Workloads = [][]*Job{opts.jobs}
+	} else {
+		if opts.maxArrival == 0 || (opts.maxLength == 0 && opts.classes == nil) {
+			fmt.Println("Ошибка: для случайных нагрузок нужны -a > 0 и -l > 0")
+			return
+		}
+		ioFreq := opts.ioFreq
+		if ioFreq == 0 {
+			ioFreq = 2
+		}
+		for i := 0; i < opts.workloads; i++ {
+			rng := rand.New(rand.NewSource(opts.seed + int64(i)))
+			if opts.classes != nil {
+				cfg.Workloads = append(cfg.Workloads, classJobs(rng, opts.classes, opts.numJobs, opts.maxArrival))
+				continue
+			}
+			cfg.Workloads = append(cfg.Workloads, randomWorkload(rng, opts.numJobs, opts.maxArrival, opts.maxLength, ioFreq))
+		}
+	}
+
+	results, err := Tune(cfg)
+	if err != nil {
+		fmt.Printf("Ошибка подбора: %v\n", err)
+		return
+	}
+	printTuneResults(cfg, results, opts.top)
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Файлы рабочей нагрузки (-f). Задачи получают ID по порядку в файле, имя
+// задается в самом файле. Формат определяется по расширению или флагом -F:
+//
+// lines (по умолчанию) — одна задача на строку, # — комментарий:
+//
+//	# имя  прибытие  длительность  [ключ=значение ...]
+//	web    0         40            io=5:2
+//	batch  10        200           queue=2
+//	editor 5                       bursts=3/4/6/2/1
+//
+// csv (.csv) — первая строка содержит названия столбцов: name, arrival,
+// length, io, io_duration, bursts, queue, ceil, floor, class. Обязательны
+// arrival и length или bursts. Пустая ячейка — значение по умолчанию.
+//
+// json (.json) — массив объектов с теми же полями и теми же обязательными
+// полями; bursts — массив чисел.
+//
+// trace (.trace) — трасса, записанная на реальной системе: строки
+// "время задача cpu|io длительность" в единицах симуляции. Первая запись
+// задачи задает ее прибытие, остальные времена — только порядок интервалов;
+// записи одного типа подряд объединяются, время всей трассы отсчитывается
+// от самой ранней записи.
+//
+// Ключи и поля задачи:
+//
+//	io=F[:D]       I/O после каждых F единиц выполнения, длительностью D
+//	bursts=C/I/C…  явные чередующиеся интервалы вычислений и I/O (как в трассе);
+//	               длительность задачи — сумма интервалов вычислений
+//	queue, ceil, floor — подсказки уровня (см. hints.go)
+//	class=NAME     класс задачи для статистики по классам (см. classes.go)
+
+// WorkloadFormats — поддерживаемые форматы файлов рабочей нагрузки
+var WorkloadFormats = []string{"lines", "csv", "json", "trace"}
+
+// IOBurst — операция I/O из явного списка интервалов задачи
+type IOBurst struct {
+	At       uint // После скольких единиц выполнения задачи начинается I/O
+	Duration uint // Длительность I/O
+}
+
+// JobSpec — описание задачи в файле рабочей нагрузки
+type JobSpec struct {
+	Name       string `json:"name,omitempty"`
+	Arrival    uint   `json:"arrival"`
+	Length     uint   `json:"length,omitempty"`      // Можно не указывать, если заданы bursts
+	IO         uint   `json:"io,omitempty"`          // Частота I/O
+	IODuration uint   `json:"io_duration,omitempty"` // Длительность I/O (0 — общая)
+	Bursts     []uint `json:"bursts,omitempty"`      // Вычисления, I/O, вычисления, ...
+	Queue      uint   `json:"queue,omitempty"`
+	Ceil       uint   `json:"ceil,omitempty"`
+	Floor      *uint  `json:"floor,omitempty"`
+	Class      string `json:"class,omitempty"`
+}
+
+// Job создает задачу с номером id по описанию
+func (s JobSpec) Job(id uint) (*Job, error) {
+	job := &Job{
+		ID:           id,
+		Name:         s.Name,
+		Class:        s.Class,
+		ArrivalTime:  s.Arrival,
+		JobLength:    s.Length,
+		IOFrequency:  s.IO,
+		IODuration:   s.IODuration,
+		InitialQueue: s.Queue,
+		Ceiling:      s.Ceil,
+		Floor:        s.Floor,
+		StartTime:    -1,
+	}
+
+	if len(s.Bursts) > 0 {
+		if s.IO > 0 {
+			return nil, fmt.Errorf("задача %s: io и bursts нельзя задавать вместе", job.Label())
+		}
+		if len(s.Bursts)%2 == 0 {
+			return nil, fmt.Errorf("задача %s: bursts должен заканчиваться интервалом вычислений", job.Label())
+		}
+		var executed uint
+		for i, d := range s.Bursts {
+			if d == 0 {
+				return nil, fmt.Errorf("задача %s: нулевой интервал в bursts", job.Label())
+			}
+			if i%2 == 0 {
+				executed += d
+			} else {
+				job.IOBursts = append(job.IOBursts, IOBurst{At: executed, Duration: d})
+			}
+		}
+		if s.Length != 0 && s.Length != executed {
+			return nil, fmt.Errorf("задача %s: длительность %d не совпадает с суммой вычислений в bursts (%d)",
+				job.Label(), s.Length, executed)
+		}
+		job.JobLength = executed
+	}
+	if job.JobLength == 0 {
+		return nil, fmt.Errorf("задача %s: не задана длительность", job.Label())
+	}
+	return job, nil
+}
+
+// LoadWorkload читает задачи из файла. Пустой format — по расширению файла.
+func LoadWorkload(path, format string) ([]*Job, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = "csv"
+		case ".json":
+			format = "json"
+		case ".trace":
+			format = "trace"
+		default:
+			format = "lines"
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var specs []JobSpec
+	switch format {
+	case "lines":
+		specs, err = parseJobLines(f)
+	case "csv":
+		specs, err = parseJobCSV(f)
+	case "json":
+		specs, err = parseJobJSON(f)
+	case "trace":
+		specs, err = parseTrace(f)
+	default:
+		return nil, fmt.Errorf("неизвестный формат: %s (доступны: %s)", format, strings.Join(WorkloadFormats, ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("%s: нет задач", path)
+	}
+
+	var jobs []*Job
+	names := make(map[string]bool)
+	for i, spec := range specs {
+		if spec.Name != "" {
+			if names[spec.Name] {
+				return nil, fmt.Errorf("%s: повторяется имя задачи %s", path, spec.Name)
+			}
+			names[spec.Name] = true
+		}
+		job, err := spec.Job(uint(i + 1))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// parseJobJSON разбирает формат json. Как и в csv, поле arrival обязательно:
+// без него задача молча прибывала бы в момент 0.
+func parseJobJSON(r io.Reader) ([]JobSpec, error) {
+	var objects []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&objects); err != nil {
+		return nil, err
+	}
+	specs := make([]JobSpec, len(objects))
+	for i, object := range objects {
+		decoder := json.NewDecoder(bytes.NewReader(object))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&specs[i]); err != nil {
+			return nil, fmt.Errorf("задача %d: %v", i+1, err)
+		}
+		var required struct {
+			Arrival *uint `json:"arrival"`
+		}
+		if err := json.Unmarshal(object, &required); err != nil {
+			return nil, fmt.Errorf("задача %d: %v", i+1, err)
+		}
+		if required.Arrival == nil {
+			return nil, fmt.Errorf("задача %d: нет поля arrival", i+1)
+		}
+	}
+	return specs, nil
+}
+
+// parseJobLines разбирает формат lines: имя, прибытие, [длительность], затем ключ=значение
+func parseJobLines(r io.Reader) ([]JobSpec, error) {
+	var specs []JobSpec
+	scanner := bufio.NewScanner(r)
+	for num := 1; scanner.Scan(); num++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var positional []string
+		var keyed bool
+		spec := JobSpec{}
+		for _, field := range fields {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				if keyed {
+					return nil, fmt.Errorf("строка %d: поле %q после ключ=значение", num, field)
+				}
+				positional = append(positional, field)
+				continue
+			}
+			keyed = true
+			if err := spec.set(key, value); err != nil {
+				return nil, fmt.Errorf("строка %d: %v", num, err)
+			}
+		}
+		if len(positional) < 2 || len(positional) > 3 {
+			return nil, fmt.Errorf("строка %d: ожидается \"имя прибытие [длительность] [ключ=значение ...]\"", num)
+		}
+		spec.Name = positional[0]
+		if err := spec.set("arrival", positional[1]); err != nil {
+			return nil, fmt.Errorf("строка %d: %v", num, err)
+		}
+		if len(positional) == 3 {
+			if err := spec.set("length", positional[2]); err != nil {
+				return nil, fmt.Errorf("строка %d: %v", num, err)
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, scanner.Err()
+}
+
+// jobSpecFields — названия полей описания задачи (ключи lines и столбцы csv)
+var jobSpecFields = []string{"name", "arrival", "length", "io", "io_duration", "bursts", "queue", "ceil", "floor", "class"}
+
+// set записывает поле описания задачи по названию (ключ в lines, столбец в csv)
+func (s *JobSpec) set(key, value string) error {
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if !slices.Contains(jobSpecFields, key) {
+		return fmt.Errorf("неизвестное поле: %s (доступны: %s)", key, strings.Join(jobSpecFields, ", "))
+	}
+	switch key {
+	case "name":
+		s.Name = value
+		return nil
+	case "class":
+		s.Class = value
+		return nil
+	}
+	if value == "" {
+		return nil
+	}
+	number := func(v string) (uint, error) {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("неверное значение %s: %s", key, v)
+		}
+		return uint(n), nil
+	}
+
+	var err error
+	switch key {
+	case "arrival":
+		s.Arrival, err = number(value)
+	case "length":
+		s.Length, err = number(value)
+	case "io":
+		// io=F или io=F:D
+		freq, duration, ok := strings.Cut(value, ":")
+		if s.IO, err = number(freq); err == nil && ok {
+			s.IODuration, err = number(duration)
+		}
+	case "io_duration":
+		s.IODuration, err = number(value)
+	case "bursts":
+		s.Bursts = nil
+		for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == '/' || r == ' ' }) {
+			d, err := number(part)
+			if err != nil {
+				return err
+			}
+			s.Bursts = append(s.Bursts, d)
+		}
+	case "queue":
+		s.Queue, err = number(value)
+	case "ceil":
+		s.Ceil, err = number(value)
+	case "floor":
+		var floor uint
+		floor, err = number(value)
+		s.Floor = &floor
+	}
+	return err
+}
+
+// parseJobCSV разбирает CSV с заголовком
+func parseJobCSV(r io.Reader) ([]JobSpec, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	for i, column := range header {
+		header[i] = strings.TrimSpace(column)
+		if !slices.Contains(jobSpecFields, header[i]) {
+			return nil, fmt.Errorf("неизвестный столбец: %s (доступны: %s)", column, strings.Join(jobSpecFields, ", "))
+		}
+		if slices.Contains(header[:i], header[i]) {
+			return nil, fmt.Errorf("столбец %s указан несколько раз", header[i])
+		}
+	}
+	if !slices.Contains(header, "arrival") {
+		return nil, fmt.Errorf("нет столбца arrival")
+	}
+	if !slices.Contains(header, "length") && !slices.Contains(header, "bursts") {
+		return nil, fmt.Errorf("нет столбца length или bursts")
+	}
+	var specs []JobSpec
+	for i, record := range records[1:] {
+		spec := JobSpec{}
+		for j, value := range record {
+			if err := spec.set(header[j], value); err != nil {
+				return nil, fmt.Errorf("строка %d: %v", i+2, err)
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// parseTrace разбирает трассу "время задача cpu|io длительность" и
+// восстанавливает по ней прибытия и интервалы задач
+func parseTrace(r io.Reader) ([]JobSpec, error) {
+	type record struct {
+		time, duration uint
+		cpu            bool
+	}
+	records := make(map[string][]record)
+	var order []string
+
+	scanner := bufio.NewScanner(r)
+	for num := 1; scanner.Scan(); num++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("строка %d: ожидается \"время задача cpu|io длительность\"", num)
+		}
+		t, err1 := strconv.ParseUint(fields[0], 10, 32)
+		d, err2 := strconv.ParseUint(fields[3], 10, 32)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("строка %d: неверное число", num)
+		}
+		var cpu bool
+		switch strings.ToLower(fields[2]) {
+		case "cpu":
+			cpu = true
+		case "io":
+		default:
+			return nil, fmt.Errorf("строка %d: неизвестный тип интервала %s (доступны: cpu, io)", num, fields[2])
+		}
+		name := fields[1]
+		if _, ok := records[name]; !ok {
+			order = append(order, name)
+		}
+		records[name] = append(records[name], record{time: uint(t), duration: uint(d), cpu: cpu})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Время трассы отсчитывается от самой ранней записи
+	var origin uint
+	for i, name := range order {
+		recs := records[name]
+		sort.SliceStable(recs, func(i, j int) bool { return recs[i].time < recs[j].time })
+		if i == 0 || recs[0].time < origin {
+			origin = recs[0].time
+		}
+	}
+
+	var specs []JobSpec
+	for _, name := range order {
+		recs := records[name]
+		start := recs[0].time
+		var bursts []uint // Вычисления, I/O, вычисления, ...
+		for _, rec := range recs {
+			if rec.duration == 0 {
+				continue
+			}
+			lastCPU := len(bursts)%2 == 1
+			switch {
+			case len(bursts) == 0 && !rec.cpu:
+				// I/O до первого вычисления откладывает прибытие задачи
+				start = rec.time + rec.duration
+			case len(bursts) > 0 && lastCPU == rec.cpu:
+				// Запись того же типа, что и предыдущая, продолжает интервал
+				bursts[len(bursts)-1] += rec.duration
+			default:
+				bursts = append(bursts, rec.duration)
+			}
+		}
+		if len(bursts) == 0 {
+			return nil, fmt.Errorf("задача %s: нет интервалов вычислений", name)
+		}
+		if len(bursts)%2 == 0 {
+			// I/O после последнего вычисления не влияет на планирование
+			bursts = bursts[:len(bursts)-1]
+		}
+		specs = append(specs, JobSpec{Name: name, Arrival: start - origin, Bursts: bursts})
+	}
+	return specs, nil
+}
+
+// dueBurst возвращает операцию I/O из списка задачи, которая должна начаться
+// при текущем количестве выполненных единиц
+func (j *Job) dueBurst() (IOBurst, bool) {
+	if j.nextBurst < len(j.IOBursts) && j.JobLength-j.TimeLeft == j.IOBursts[j.nextBurst].At {
+		return j.IOBursts[j.nextBurst], true
+	}
+	return IOBurst{}, false
+}
+
+// Label возвращает ID задачи и ее имя, если оно задано, например "3 (web)"
+func (j *Job) Label() string {
+	if j.Name == "" {
+		return strconv.FormatUint(uint64(j.ID), 10)
+	}
+	return fmt.Sprintf("%d (%s)", j.ID, j.Name)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// jobSummary описывает поля задачи, которые задает файл нагрузки
+func jobSummary(job *Job) string {
+	s := fmt.Sprintf("%d %s@%d len=%d", job.ID, job.Name, job.ArrivalTime, job.JobLength)
+	if job.IOFrequency > 0 || job.IODuration > 0 {
+		s += fmt.Sprintf(" io=%d:%d", job.IOFrequency, job.IODuration)
+	}
+	for _, burst := range job.IOBursts {
+		s += fmt.Sprintf(" io@%d:%d", burst.At, burst.Duration)
+	}
+	if job.InitialQueue > 0 || job.Ceiling > 0 {
+		s += fmt.Sprintf(" queue=%d ceil=%d", job.InitialQueue, job.Ceiling)
+	}
+	if job.Floor != nil {
+		s += fmt.Sprintf(" floor=%d", *job.Floor)
+	}
+	if job.Class != "" {
+		s += " class=" + job.Class
+	}
+	return s
+}
+
+// workloadCase — содержимое файла нагрузки и ожидаемые задачи или ошибка
+type workloadCase struct {
+	name   string
+	file   string // Имя файла: расширение определяет формат
+	format string // -F
+	src    string
+	jobs   []string // jobSummary задач
+	err    string   // Подстрока ожидаемой ошибки
+}
+
+var workloadCases = []workloadCase{
+	// lines
+	{
+		name: "lines",
+		file: "w.txt",
+		src: "# имя прибытие длительность\n" +
+			"web    0  40 io=5:2 class=interactive\n" +
+			"batch  10 200 queue=2 ceil=1 floor=2  # комментарий\n" +
+			"\n" +
+			"editor 5 bursts=3/4/6/2/1\n",
+		jobs: []string{
+			"1 web@0 len=40 io=5:2 class=interactive",
+			"2 batch@10 len=200 queue=2 ceil=1 floor=2",
+			"3 editor@5 len=10 io@3:4 io@9:2",
+		},
+	},
+	{name: "lines: длительность из bursts совпадает", file: "w.txt", src: "a 0 5 bursts=2/1/3", jobs: []string{"1 a@0 len=5 io@2:1"}},
+	{name: "lines: позиционное поле после ключа", file: "w.txt", src: "a 0 io=5 10", err: "строка 1: поле \"10\" после ключ=значение"},
+	{name: "lines: мало полей", file: "w.txt", src: "a 0 10\nb", err: "строка 2: ожидается \"имя прибытие"},
+	{name: "lines: много полей", file: "w.txt", src: "a 0 10 20", err: "строка 1: ожидается \"имя прибытие"},
+	{name: "lines: неизвестный ключ", file: "w.txt", src: "a 0 10 prio=1", err: "строка 1: неизвестное поле: prio"},
+	{name: "lines: неверное число", file: "w.txt", src: "a x 10", err: "строка 1: неверное значение arrival: x"},
+	{name: "lines: неверная длительность I/O", file: "w.txt", src: "a 0 10 io=5:x", err: "неверное значение io: x"},
+	{name: "lines: нет длительности", file: "w.txt", src: "a 0", err: "задача 1 (a): не задана длительность"},
+	{name: "lines: нулевая длительность", file: "w.txt", src: "a 0 0", err: "задача 1 (a): не задана длительность"},
+	{name: "lines: повторное имя", file: "w.txt", src: "a 0 10\na 5 10", err: "повторяется имя задачи a"},
+	{name: "lines: пустой файл", file: "w.txt", src: "# только комментарий\n", err: "нет задач"},
+
+	// bursts и length
+	{name: "bursts: длительность не совпадает", file: "w.txt", src: "a 0 6 bursts=2/1/3", err: "длительность 6 не совпадает с суммой вычислений в bursts (5)"},
+	{name: "bursts: вместе с io", file: "w.txt", src: "a 0 bursts=2/1/3 io=2", err: "io и bursts нельзя задавать вместе"},
+	{name: "bursts: заканчивается I/O", file: "w.txt", src: "a 0 bursts=2/1", err: "bursts должен заканчиваться интервалом вычислений"},
+	{name: "bursts: нулевой интервал", file: "w.txt", src: "a 0 bursts=2/0/3", err: "нулевой интервал в bursts"},
+	{name: "bursts: неверное число", file: "w.txt", src: "a 0 bursts=2/x/3", err: "неверное значение bursts: x"},
+
+	// csv
+	{
+		name: "csv",
+		file: "w.csv",
+		src: "name, arrival, length, io, io_duration, bursts, queue, ceil, floor, class\n" +
+			"# комментарий\n" +
+			"web, 0, 40, 5, 2, , , , , interactive\n" +
+			"batch, 10, 200, , , , 2, 1, 2,\n" +
+			"editor, 5, , , , 3/4/6, , , ,\n",
+		jobs: []string{
+			"1 web@0 len=40 io=5:2 class=interactive",
+			"2 batch@10 len=200 queue=2 ceil=1 floor=2",
+			"3 editor@5 len=9 io@3:4",
+		},
+	},
+	{name: "csv: столбцы в другом порядке", file: "w.csv", src: "length,arrival\n10,3\n", jobs: []string{"1 @3 len=10"}},
+	{name: "csv: только заголовок", file: "w.csv", src: "arrival,length\n", err: "нет задач"},
+	{name: "csv: неизвестный столбец", file: "w.csv", src: "arrival,length,prio\n0,10,1\n", err: "неизвестный столбец: prio"},
+	{name: "csv: повторный столбец", file: "w.csv", src: "arrival,length,arrival\n0,10,1\n", err: "столбец arrival указан несколько раз"},
+	{name: "csv: нет arrival", file: "w.csv", src: "name,length\na,10\n", err: "нет столбца arrival"},
+	{name: "csv: нет length и bursts", file: "w.csv", src: "name,arrival\na,0\n", err: "нет столбца length или bursts"},
+	{name: "csv: лишняя ячейка", file: "w.csv", src: "arrival,length\n0,10,5\n", err: "wrong number of fields"},
+	{name: "csv: неверное число", file: "w.csv", src: "arrival,length\n0,-1\n", err: "строка 2: неверное значение length: -1"},
+	{name: "csv: пустая длительность", file: "w.csv", src: "name,arrival,length,bursts\na,0,,\n", err: "задача 1 (a): не задана длительность"},
+	{name: "csv: length и bursts не совпадают", file: "w.csv", src: "arrival,length,bursts\n0,4,2/1/3\n", err: "длительность 4 не совпадает"},
+
+	// json
+	{
+		name: "json",
+		file: "w.json",
+		src: `[{"name": "web", "arrival": 0, "length": 40, "io": 5, "io_duration": 2, "class": "interactive"},
+			{"name": "batch", "arrival": 10, "length": 200, "queue": 2, "ceil": 1, "floor": 2},
+			{"arrival": 5, "bursts": [3, 4, 6]}]`,
+		jobs: []string{
+			"1 web@0 len=40 io=5:2 class=interactive",
+			"2 batch@10 len=200 queue=2 ceil=1 floor=2",
+			"3 @5 len=9 io@3:4",
+		},
+	},
+	{name: "json: неизвестное поле", file: "w.json", src: `[{"arrival": 0, "length": 10, "prio": 1}]`, err: "unknown field \"prio\""},
+	{name: "json: нет arrival", file: "w.json", src: `[{"arrival": 0, "length": 10}, {"name": "b", "length": 10}]`, err: "задача 2: нет поля arrival"},
+	{name: "json: null вместо массива", file: "w.json", src: `null`, err: "нет задач"},
+	{name: "json: пустой массив", file: "w.json", src: `[]`, err: "нет задач"},
+	{name: "json: неверный тип", file: "w.json", src: `[{"arrival": "0", "length": 10}]`, err: "cannot unmarshal"},
+	{name: "json: length и bursts не совпадают", file: "w.json", src: `[{"arrival": 0, "length": 10, "bursts": [3, 4, 6]}]`, err: "длительность 10 не совпадает"},
+
+	// trace
+	{
+		name: "trace",
+		file: "w.trace",
+		src: "# время задача тип длительность\n" +
+			"100 web cpu 3\n" +
+			"103 web io 4\n" +
+			"107 web cpu 2\n" +
+			"109 web cpu 1   # продолжение вычисления\n" +
+			"110 web io 5\n" +
+			"105 db  io 10\n" +
+			"115 db  cpu 20\n",
+		jobs: []string{
+			"1 web@0 len=6 io@3:4",
+			"2 db@15 len=20",
+		},
+	},
+	{name: "trace: записи не по порядку", file: "w.trace", src: "20 a cpu 2\n10 a cpu 3\n15 a io 1\n", jobs: []string{"1 a@0 len=5 io@3:1"}},
+	{
+		name: "trace: нулевые интервалы пропускаются",
+		file: "w.trace",
+		src:  "0 a cpu 2\n2 a io 0\n2 a cpu 3\n5 a io 4\n9 a cpu 0\n9 a cpu 1\n",
+		jobs: []string{"1 a@0 len=6 io@5:4"},
+	},
+	{
+		// Первая запись задает прибытие, даже если ее длительность 0
+		name: "trace: нулевая первая запись",
+		file: "w.trace",
+		src:  "0 a cpu 1\n3 b cpu 0\n8 b cpu 2\n",
+		jobs: []string{"1 a@0 len=1", "2 b@3 len=2"},
+	},
+	{name: "trace: только нулевые интервалы", file: "w.trace", src: "0 a cpu 0\n0 a io 0\n", err: "задача a: нет интервалов вычислений"},
+	{name: "trace: только I/O", file: "w.trace", src: "0 a io 5\n", err: "задача a: нет интервалов вычислений"},
+	{name: "trace: мало полей", file: "w.trace", src: "0 a cpu\n", err: "строка 1: ожидается \"время задача cpu|io длительность\""},
+	{name: "trace: неизвестный тип", file: "w.trace", src: "0 a net 5\n", err: "строка 1: неизвестный тип интервала net"},
+	{name: "trace: неверное число", file: "w.trace", src: "0 a cpu -5\n", err: "строка 1: неверное число"},
+
+	// Формат
+	{name: "формат из -F", file: "w.dat", format: "csv", src: "arrival,length\n0,10\n", jobs: []string{"1 @0 len=10"}},
+	{name: "неизвестный формат", file: "w.dat", format: "xml", src: "<jobs/>", err: "неизвестный формат: xml"},
+}
+
+func TestLoadWorkload(t *testing.T) {
+	dir := t.TempDir()
+	for i, c := range workloadCases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("%d-%s", i, c.file))
+			if err := os.WriteFile(path, []byte(c.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			jobs, err := LoadWorkload(path, c.format)
+			if c.err != "" {
+				if err == nil {
+					t.Fatalf("ожидается ошибка %q", c.err)
+				}
+				if !strings.Contains(err.Error(), c.err) {
+					t.Errorf("ошибка %q, ожидается %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ошибка: %v", err)
+			}
+			var got []string
+			for _, job := range jobs {
+				got = append(got, jobSummary(job))
+			}
+			if strings.Join(got, "\n") != strings.Join(c.jobs, "\n") {
+				t.Errorf("задачи:\n%s\nожидается:\n%s", strings.Join(got, "\n"), strings.Join(c.jobs, "\n"))
+			}
+		})
+	}
+}
+
+// TestSampleWorkloads проверяет, что файлы нагрузки из репозитория читаются
+func TestSampleWorkloads(t *testing.T) {
+	for _, path := range []string{"workload.txt", "sample.trace"} {
+		if _, err := LoadWorkload(path, ""); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+}
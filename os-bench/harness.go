@@ -0,0 +1,347 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Схема измерений. Испытания измеряются по инвариантному TSC (см. tsc.go), а
+// без него — по time.Now. Сначала измеряются разрешение таймера и стоимость
+// измерения пустого интервала. Затем для каждого измерения подбирается
+// количество операций в испытании, чтобы испытание длилось много дольше
+// разрешения таймера, и испытания повторяются: сначала до окончания разогрева
+// (медиана последних испытаний перестает меняться), потом заданное количество
+// раз. По результатам испытаний выводятся min, медиана, среднее,
+// стандартное отклонение, p99 (при меньше чем p99MinTrials испытаниях — max),
+// выбросы (за пределами 1.5 межквартильного размаха) и бутстреп-интервал для
+// среднего.
+
+// p99MinTrials — наименьшее количество испытаний, при котором выводится p99:
+// при меньшем количестве он лишь интерполирует два наибольших значения
+const p99MinTrials = 100
+
+// Harness — параметры серии испытаний
+type Harness struct {
+	Trials       int           // Количество измеряемых испытаний
+	MinTrialTime time.Duration // Минимальная длительность одного испытания
+	WarmupWindow int           // Окно испытаний для определения окончания разогрева
+	MaxWarmup    int           // Наибольшее количество испытаний разогрева
+	Bootstrap    int           // Количество выборок бутстрепа
+	Confidence   float64       // Доверительная вероятность интервала
+
+	Resolution time.Duration // Наименьший шаг таймера
+	Overhead   time.Duration // Стоимость измерения пустого интервала
+
+	TSC       *TSC   // Счетчик тактов (nil — испытания измеряются по time.Now)
+	TSCReason string // Почему TSC не используется
+}
+
+// NewHarness измеряет таймер и возвращает параметры по умолчанию
+func NewHarness() *Harness {
+	h := &Harness{
+		Trials:       30,
+		WarmupWindow: 5,
+		MaxWarmup:    50,
+		Bootstrap:    2000,
+		Confidence:   0.95,
+	}
+	h.TSC, h.TSCReason = detectTSC()
+	h.measureTimer()
+	// Погрешность таймера — не больше 0.1% испытания
+	h.MinTrialTime = max(5*time.Millisecond, 1000*h.Resolution)
+	return h
+}
+
+// measureTimer определяет разрешение таймера как наименьшую ненулевую
+// разность соседних показаний и стоимость измерения пустого интервала
+func (h *Harness) measureTimer() {
+	const calls = 100000
+	if h.TSC != nil {
+		resolution := uint64(math.MaxUint64)
+		var total uint64
+		for i := 0; i < calls; i++ {
+			c0 := h.TSC.start()
+			c1 := h.TSC.end()
+			if d := c1 - c0; d > 0 {
+				resolution = min(resolution, d)
+			}
+			total += c1 - c0
+		}
+		h.Resolution = time.Duration(math.Ceil(float64(resolution) / h.TSC.GHz))
+		h.Overhead = time.Duration(float64(total) / calls / h.TSC.GHz)
+		return
+	}
+
+	h.Resolution = time.Duration(math.MaxInt64)
+	prev := time.Now()
+	start := prev
+	for i := 0; i < calls; i++ {
+		now := time.Now()
+		if d := now.Sub(prev); d > 0 && d < h.Resolution {
+			h.Resolution = d
+		}
+		prev = now
+	}
+	h.Overhead = prev.Sub(start) / calls
+}
+
+// PrintTimer выводит характеристики таймера
+func (h *Harness) PrintTimer() {
+	fmt.Println("=== Таймер ===")
+	if h.TSC != nil {
+		end := "RDTSC"
+		if h.TSC.RDTSCP {
+			end = "RDTSCP"
+		}
+		fmt.Printf("Инвариантный TSC: %.3f ГГц (калибровка по CLOCK_MONOTONIC), конец интервала: %s\n", h.TSC.GHz, end)
+	} else {
+		fmt.Printf("Время по time.Now (CLOCK_MONOTONIC): %s\n", h.TSCReason)
+	}
+	fmt.Printf("Разрешение: %v, стоимость измерения: %v\n", h.Resolution, h.Overhead)
+	fmt.Printf("Испытание: не короче %v, испытаний: %d, разогрев: до %d испытаний\n",
+		h.MinTrialTime, h.Trials, h.MaxWarmup)
+}
+
+// Result — результаты серии испытаний, в наносекундах на операцию
+type Result struct {
+	Name       string
+	Iterations int       // Операций в одном испытании
+	Warmup     int       // Испытаний разогрева
+	Stable     bool      // Разогрев закончился до MaxWarmup
+	Trials     []float64 // Время операции в каждом испытании, в порядке испытаний
+
+	Min, Median, Mean, StdDev, P99, Max float64
+	CILow, CIHigh                       float64 // Доверительный интервал для среднего
+	Confidence                          float64
+	Outliers                            []bool  // Испытание — выброс
+	GHz                                 float64 // Частота TSC для вывода в тактах (0 — только наносекунды)
+}
+
+// Run измеряет операцию op; op(n) выполняет n операций
+func (h *Harness) Run(name string, op func(n int)) Result {
+	n := h.calibrate(op)
+	trial := func() float64 {
+		var elapsed float64
+		if h.TSC != nil {
+			c0 := h.TSC.start()
+			op(n)
+			c1 := h.TSC.end()
+			elapsed = float64(c1-c0) / h.TSC.GHz
+		} else {
+			start := time.Now()
+			op(n)
+			elapsed = float64(time.Since(start).Nanoseconds())
+		}
+		return (elapsed - float64(h.Overhead.Nanoseconds())) / float64(n)
+	}
+
+	r := Result{Name: name, Iterations: n, Confidence: h.Confidence}
+	if h.TSC != nil {
+		r.GHz = h.TSC.GHz
+	}
+	// Разогрев: пока медиана последнего окна отличается от медианы
+	// предыдущего больше чем на 5%
+	var warmup []float64
+	for len(warmup) < h.MaxWarmup {
+		warmup = append(warmup, trial())
+		if k := h.WarmupWindow; len(warmup) >= 2*k {
+			last := median(warmup[len(warmup)-k:])
+			prev := median(warmup[len(warmup)-2*k : len(warmup)-k])
+			if math.Abs(last-prev) <= 0.05*prev {
+				r.Stable = true
+				break
+			}
+		}
+	}
+	r.Warmup = len(warmup)
+
+	// Хотя бы одно испытание: статистикам нужна непустая выборка
+	for i := 0; i < max(h.Trials, 1); i++ {
+		r.Trials = append(r.Trials, trial())
+	}
+	r.summarize(h.Bootstrap)
+	return r
+}
+
+// calibrate подбирает количество операций, при котором испытание длится не
+// меньше MinTrialTime
+func (h *Harness) calibrate(op func(n int)) int {
+	n := 1
+	for {
+		start := time.Now()
+		op(n)
+		elapsed := time.Since(start)
+		if elapsed >= h.MinTrialTime || n >= 1<<30 {
+			return n
+		}
+		// Оцениваем нужное количество по скорости, но не больше чем в 100 раз за шаг
+		next := 100 * n
+		if elapsed > 0 {
+			next = min(next, int(float64(n)*1.2*float64(h.MinTrialTime)/float64(elapsed)))
+		}
+		n = max(next, n+1)
+	}
+}
+
+// summarize вычисляет статистики по испытаниям
+func (r *Result) summarize(resamples int) {
+	sorted := append([]float64(nil), r.Trials...)
+	sort.Float64s(sorted)
+	r.Min, r.Max = sorted[0], sorted[len(sorted)-1]
+	r.Median = percentile(sorted, 0.5)
+	r.P99 = percentile(sorted, 0.99)
+	r.Mean = mean(sorted)
+	var sq float64
+	for _, v := range sorted {
+		sq += (v - r.Mean) * (v - r.Mean)
+	}
+	if len(sorted) > 1 {
+		r.StdDev = math.Sqrt(sq / float64(len(sorted)-1))
+	}
+
+	// Выбросы по Тьюки: дальше 1.5 межквартильного размаха от квартилей
+	q1, q3 := percentile(sorted, 0.25), percentile(sorted, 0.75)
+	low, high := q1-1.5*(q3-q1), q3+1.5*(q3-q1)
+	r.Outliers = make([]bool, len(r.Trials))
+	for i, v := range r.Trials {
+		r.Outliers[i] = v < low || v > high
+	}
+
+	// Бутстреп: распределение среднего по выборкам с возвращением.
+	// Семя фиксировано, чтобы интервал не менялся от запуска к запуску.
+	rng := rand.New(rand.NewSource(1))
+	means := make([]float64, resamples)
+	sample := make([]float64, len(sorted))
+	for i := range means {
+		means[i] = resampleMean(rng, sorted, sample)
+	}
+	r.CILow, r.CIHigh = interval(means, r.Confidence)
+}
+
+// Adjust возвращает результат для величины (v + offset) * scale, например
+// время одного переключения по времени обмена
+func (r Result) Adjust(name string, offset, scale float64) Result {
+	f := func(v float64) float64 { return (v + offset) * scale }
+	adjusted := r
+	adjusted.Name = name
+	adjusted.Trials = make([]float64, len(r.Trials))
+	for i, v := range r.Trials {
+		adjusted.Trials[i] = f(v)
+	}
+	adjusted.Min, adjusted.Median, adjusted.Mean, adjusted.P99, adjusted.Max = f(r.Min), f(r.Median), f(r.Mean), f(r.P99), f(r.Max)
+	adjusted.CILow, adjusted.CIHigh = f(r.CILow), f(r.CIHigh)
+	adjusted.StdDev = r.StdDev * math.Abs(scale)
+	if scale < 0 {
+		adjusted.Min, adjusted.Max = adjusted.Max, adjusted.Min
+		adjusted.CILow, adjusted.CIHigh = adjusted.CIHigh, adjusted.CILow
+	}
+	return adjusted
+}
+
+// Difference возвращает результат для величины (r - k*other) * scale, где
+// other — отдельно измеренная стоимость, например записи и чтения канала.
+// Испытания r сдвигаются на среднее other, а доверительный интервал строится
+// бутстрепом по обеим сериям испытаний, чтобы учесть погрешность обеих.
+func (h *Harness) Difference(name string, r, other Result, k, scale float64) Result {
+	adjusted := r.Adjust(name, -k*other.Mean, scale)
+	rng := rand.New(rand.NewSource(1))
+	diffs := make([]float64, h.Bootstrap)
+	sample := make([]float64, len(r.Trials))
+	otherSample := make([]float64, len(other.Trials))
+	for i := range diffs {
+		diffs[i] = (resampleMean(rng, r.Trials, sample) - k*resampleMean(rng, other.Trials, otherSample)) * scale
+	}
+	adjusted.CILow, adjusted.CIHigh = interval(diffs, r.Confidence)
+	return adjusted
+}
+
+// Print выводит статистики и испытания; выбросы отмечены звездочкой
+func (r Result) Print() {
+	fmt.Printf("%s:\n", r.Name)
+	warmup := fmt.Sprintf("разогрев %d испытаний", r.Warmup)
+	if !r.Stable {
+		warmup += " (не стабилизировалось)"
+	}
+	fmt.Printf("  %d испытаний по %d операций, %s\n", len(r.Trials), r.Iterations, warmup)
+	tailName, tail := r.tail()
+	fmt.Printf("  min %.1f нс, медиана %.1f нс, среднее %.1f нс, σ %.1f нс, %s %.1f нс\n",
+		r.Min, r.Median, r.Mean, r.StdDev, tailName, tail)
+	fmt.Printf("  %.0f%% доверительный интервал для среднего: [%.1f, %.1f] нс\n",
+		100*r.Confidence, r.CILow, r.CIHigh)
+	if r.GHz > 0 {
+		fmt.Printf("  В тактах TSC: min %.0f, медиана %.0f, среднее %.0f, %s %.0f, интервал [%.0f, %.0f]\n",
+			r.Min*r.GHz, r.Median*r.GHz, r.Mean*r.GHz, tailName, tail*r.GHz, r.CILow*r.GHz, r.CIHigh*r.GHz)
+	}
+
+	var trials []string
+	outliers := 0
+	for i, v := range r.Trials {
+		mark := ""
+		if r.Outliers[i] {
+			mark = "*"
+			outliers++
+		}
+		trials = append(trials, fmt.Sprintf("%s%.0f", mark, v))
+	}
+	fmt.Printf("  Выбросов: %d; испытания, нс: %s\n", outliers, strings.Join(trials, " "))
+}
+
+// tail возвращает название и значение верхнего хвоста распределения: p99,
+// если испытаний достаточно, иначе максимум
+func (r Result) tail() (string, float64) {
+	if len(r.Trials) < p99MinTrials {
+		return "max", r.Max
+	}
+	return "p99", r.P99
+}
+
+// mean возвращает среднее значение
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// resampleMean заполняет sample выборкой с возвращением из values и
+// возвращает ее среднее
+func resampleMean(rng *rand.Rand, values, sample []float64) float64 {
+	for j := range sample {
+		sample[j] = values[rng.Intn(len(values))]
+	}
+	return mean(sample)
+}
+
+// interval возвращает центральный интервал значений с вероятностью
+// confidence; values упорядочиваются
+func interval(values []float64, confidence float64) (low, high float64) {
+	sort.Float64s(values)
+	alpha := (1 - confidence) / 2
+	return percentile(values, alpha), percentile(values, 1-alpha)
+}
+
+// median возвращает медиану неупорядоченных значений
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return percentile(sorted, 0.5)
+}
+
+// percentile возвращает квантиль p упорядоченных значений с линейной
+// интерполяцией
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (pos-float64(i))*(sorted[i+1]-sorted[i])
+}
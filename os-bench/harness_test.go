@@ -0,0 +1,77 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40}
+	cases := []struct {
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{sorted, 0, 10},
+		{sorted, 1, 40},
+		{sorted, 0.5, 25},
+		{sorted, 0.25, 17.5},
+		{sorted, 0.99, 39.7},
+		{[]float64{7}, 0.5, 7},
+		{nil, 0.5, 0},
+	}
+	for _, c := range cases {
+		if got := percentile(c.values, c.p); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("percentile(%v, %g) = %g, ожидается %g", c.values, c.p, got, c.want)
+		}
+	}
+}
+
+func TestInterval(t *testing.T) {
+	// 101 значение 0..100 в обратном порядке: interval упорядочивает их сам
+	values := make([]float64, 101)
+	for i := range values {
+		values[i] = float64(100 - i)
+	}
+	low, high := interval(values, 0.9)
+	if math.Abs(low-5) > 1e-9 || math.Abs(high-95) > 1e-9 {
+		t.Errorf("интервал 90%%: [%g, %g], ожидается [5, 95]", low, high)
+	}
+	low, high = interval(values, 0)
+	if low != 50 || high != 50 {
+		t.Errorf("интервал 0%%: [%g, %g], ожидается [50, 50]", low, high)
+	}
+}
+
+func TestSummarizeOutliers(t *testing.T) {
+	// Квартили 10 и 12, границы Тьюки 7 и 15
+	r := Result{Confidence: 0.95, Trials: []float64{11, 10, 30, 12, 10, 6, 11, 12, 15, 7}}
+	r.summarize(100)
+	want := []bool{false, false, true, false, false, true, false, false, false, false}
+	if !reflect.DeepEqual(r.Outliers, want) {
+		t.Errorf("выбросы %v, ожидается %v", r.Outliers, want)
+	}
+	if r.Min != 6 || r.Max != 30 || r.Median != 11 {
+		t.Errorf("min %g, max %g, медиана %g, ожидается 6, 30, 11", r.Min, r.Max, r.Median)
+	}
+	if r.CILow > r.Mean || r.CIHigh < r.Mean {
+		t.Errorf("интервал [%g, %g] не содержит среднее %g", r.CILow, r.CIHigh, r.Mean)
+	}
+}
+
+func TestResultTail(t *testing.T) {
+	r := Result{Confidence: 0.95}
+	for i := 0; i < p99MinTrials-1; i++ {
+		r.Trials = append(r.Trials, float64(i))
+	}
+	r.summarize(10)
+	if name, v := r.tail(); name != "max" || v != float64(p99MinTrials-2) {
+		t.Errorf("%d испытаний: %s %g, ожидается max %d", len(r.Trials), name, v, p99MinTrials-2)
+	}
+	r.Trials = append(r.Trials, float64(p99MinTrials-1))
+	r.summarize(10)
+	if name, v := r.tail(); name != "p99" || v != r.P99 {
+		t.Errorf("%d испытаний: %s %g, ожидается p99 %g", len(r.Trials), name, v, r.P99)
+	}
+}
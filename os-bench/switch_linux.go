@@ -0,0 +1,231 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// Переключение контекста между процессами, как lat_ctx в lmbench: программа
+// запускает свою копию, оба процесса закрепляются за одним CPU
+// (sched_setaffinity) и передают друг другу байт через два канала (pipe).
+// Каждый обмен — два переключения контекста, две записи и два чтения;
+// стоимость записи и чтения измеряется отдельно в одном процессе и вычитается.
+
+// switchChildEnv — переменная окружения, по которой копия программы узнает,
+// что она второй процесс измерения; значение — номер CPU
+const switchChildEnv = "OS_BENCH_SWITCH_CPU"
+
+// Дескрипторы каналов во втором процессе (exec.Cmd.ExtraFiles)
+const (
+	childReadFd  = 3
+	childWriteFd = 4
+)
+
+// cpuMask — маска CPU для sched_setaffinity (до 1024 CPU)
+type cpuMask [16]uint64
+
+// setAffinity закрепляет текущий поток за CPU
+func setAffinity(cpu int) error {
+	var mask cpuMask
+	mask[cpu/64] |= 1 << (cpu % 64)
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY, 0, unsafe.Sizeof(mask), uintptr(unsafe.Pointer(&mask)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// firstAllowedCPU возвращает первый CPU, на котором разрешено выполняться
+// текущему потоку
+func firstAllowedCPU() (int, error) {
+	var mask cpuMask
+	_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY, 0, unsafe.Sizeof(mask), uintptr(unsafe.Pointer(&mask)))
+	if errno != 0 {
+		return 0, errno
+	}
+	for cpu := 0; cpu < len(mask)*64; cpu++ {
+		if mask[cpu/64]&(1<<(cpu%64)) != 0 {
+			return cpu, nil
+		}
+	}
+	return 0, fmt.Errorf("пустая маска CPU")
+}
+
+// readByte читает один байт из блокирующего дескриптора
+func readByte(fd int, buf []byte) error {
+	for {
+		n, err := syscall.Read(fd, buf[:1])
+		switch {
+		case err == syscall.EINTR:
+			continue
+		case err != nil:
+			return err
+		case n == 0:
+			return fmt.Errorf("канал закрыт")
+		}
+		return nil
+	}
+}
+
+// writeByte записывает один байт в блокирующий дескриптор
+func writeByte(fd int, buf []byte) error {
+	for {
+		_, err := syscall.Write(fd, buf[:1])
+		if err == syscall.EINTR {
+			continue
+		}
+		return err
+	}
+}
+
+// runAsSwitchChild выполняет роль второго процесса, если программа запущена
+// measureProcessContextSwitch, и возвращает true
+func runAsSwitchChild() bool {
+	value, ok := os.LookupEnv(switchChildEnv)
+	if !ok {
+		return false
+	}
+	runtime.LockOSThread()
+
+	// Первый байт сообщает, удалось ли закрепиться за CPU
+	buf := []byte{1}
+	if cpu, err := strconv.Atoi(value); err != nil || setAffinity(cpu) != nil {
+		buf[0] = 0
+	}
+	if writeByte(childWriteFd, buf) != nil {
+		os.Exit(1)
+	}
+	// Возвращаем каждый байт, пока первый процесс не закроет канал
+	for readByte(childReadFd, buf) == nil {
+		if writeByte(childWriteFd, buf) != nil {
+			os.Exit(1)
+		}
+	}
+	return true
+}
+
+// measurePipeOverhead измеряет запись и чтение байта через канал в одном
+// процессе, без переключения контекста
+func measurePipeOverhead(h *Harness) (Result, error) {
+	var p [2]int
+	if err := syscall.Pipe2(p[:], syscall.O_CLOEXEC); err != nil {
+		return Result{}, err
+	}
+	defer syscall.Close(p[0])
+	defer syscall.Close(p[1])
+
+	buf := []byte{1}
+	return h.Run("Запись и чтение pipe в одном процессе", func(n int) {
+		for i := 0; i < n; i++ {
+			writeByte(p[1], buf)
+			readByte(p[0], buf)
+		}
+	}), nil
+}
+
+// measureProcessContextSwitch измеряет переключение контекста между двумя
+// процессами, закрепленными за одним CPU
+func measureProcessContextSwitch(h *Harness) {
+	fmt.Println("\n--- Измерение между процессами (как lat_ctx в lmbench) ---")
+
+	// Измерение идет в одном потоке ОС, закрепленном за CPU
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	cpu, err := firstAllowedCPU()
+	if err != nil {
+		fmt.Printf("Ошибка sched_getaffinity: %v\n", err)
+		return
+	}
+	pinned := true
+	if err := setAffinity(cpu); err != nil {
+		fmt.Printf("Не удалось закрепиться за CPU %d: %v\n", cpu, err)
+		pinned = false
+	}
+
+	overhead, err := measurePipeOverhead(h)
+	if err != nil {
+		fmt.Printf("Ошибка создания pipe: %v\n", err)
+		return
+	}
+
+	// toChild: первый процесс пишет, второй читает; fromChild — наоборот
+	var toChild, fromChild [2]int
+	if err := syscall.Pipe2(toChild[:], syscall.O_CLOEXEC); err != nil {
+		fmt.Printf("Ошибка создания pipe1: %v\n", err)
+		return
+	}
+	if err := syscall.Pipe2(fromChild[:], syscall.O_CLOEXEC); err != nil {
+		syscall.Close(toChild[0])
+		syscall.Close(toChild[1])
+		fmt.Printf("Ошибка создания pipe2: %v\n", err)
+		return
+	}
+	defer syscall.Close(fromChild[0])
+
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "/proc/self/exe"
+	}
+	childRead := os.NewFile(uintptr(toChild[0]), "pipe1")
+	childWrite := os.NewFile(uintptr(fromChild[1]), "pipe2")
+	cmd := exec.Command(exe)
+	cmd.Env = append(os.Environ(), switchChildEnv+"="+strconv.Itoa(cpu), "GOMAXPROCS=1")
+	cmd.ExtraFiles = []*os.File{childRead, childWrite}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Start()
+	// Концы каналов второго процесса нужны только ему
+	childRead.Close()
+	childWrite.Close()
+	if err != nil {
+		syscall.Close(toChild[1])
+		fmt.Printf("Ошибка запуска второго процесса: %v\n", err)
+		return
+	}
+	// Закрытый канал завершает второй процесс
+	defer func() {
+		syscall.Close(toChild[1])
+		cmd.Wait()
+	}()
+
+	buf := []byte{1}
+	if err := readByte(fromChild[0], buf); err != nil {
+		fmt.Printf("Второй процесс не ответил: %v\n", err)
+		return
+	}
+	if buf[0] == 0 {
+		pinned = false
+	}
+	if pinned {
+		fmt.Printf("Оба процесса закреплены за CPU %d\n", cpu)
+	} else {
+		fmt.Println("Процессы не закреплены за одним CPU: результат может включать обмен между CPU")
+	}
+
+	var exchangeErr error
+	roundTrip := h.Run("Обмен между процессами", func(n int) {
+		for i := 0; i < n && exchangeErr == nil; i++ {
+			if exchangeErr = writeByte(toChild[1], buf); exchangeErr == nil {
+				exchangeErr = readByte(fromChild[0], buf)
+			}
+		}
+	})
+	if exchangeErr != nil {
+		fmt.Printf("Ошибка обмена: %v\n", exchangeErr)
+		return
+	}
+	overhead.Print()
+	roundTrip.Print()
+
+	// Обмен: два переключения и две пары запись+чтение; вычитаем среднюю
+	// стоимость записи и чтения, интервал учитывает погрешность обоих измерений
+	h.Difference("Переключение контекста между процессами", roundTrip, overhead, 2, 0.5).Print()
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package main
+
+import "fmt"
+
+// runAsSwitchChild: второй процесс измерения запускается только на Linux
+func runAsSwitchChild() bool {
+	return false
+}
+
+// measureProcessContextSwitch требует sched_setaffinity, которого нет вне Linux
+func measureProcessContextSwitch(h *Harness) {
+	fmt.Println("\n--- Измерение между процессами ---")
+	fmt.Println("Доступно только на Linux (нужен sched_setaffinity)")
+}
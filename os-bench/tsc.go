@@ -0,0 +1,39 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// Измерение времени в тактах TSC (Time Stamp Counter). Частота счетчика
+// определяется по CLOCK_MONOTONIC: монотонные показания time.Now на Linux
+// берутся из него. Инвариантный TSC идет с номинальной частотой процессора,
+// поэтому его такты — это такты номинальной частоты, а не фактические такты
+// ядра при турбо-режиме или сниженной частоте.
+
+// TSC — откалиброванный счетчик тактов
+type TSC struct {
+	Invariant bool    // Частота счетчика постоянна
+	RDTSCP    bool    // Конец интервала читается инструкцией RDTSCP
+	GHz       float64 // Тактов счетчика в наносекунде
+
+	start, end func() uint64 // Чтение счетчика в начале и в конце интервала
+}
+
+// calibrate измеряет частоту TSC по CLOCK_MONOTONIC: несколько интервалов
+// по 20 мс, берется медиана
+func (t *TSC) calibrate() {
+	const rounds, interval = 5, 20 * time.Millisecond
+	var samples []float64
+	for i := 0; i < rounds; i++ {
+		start := time.Now()
+		c0 := t.start()
+		for time.Since(start) < interval {
+		}
+		c1 := t.end()
+		elapsed := time.Since(start)
+		samples = append(samples, float64(c1-c0)/float64(elapsed.Nanoseconds()))
+	}
+	sort.Float64s(samples)
+	t.GHz = samples[len(samples)/2]
+}
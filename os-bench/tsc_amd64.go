@@ -0,0 +1,37 @@
+package main
+
+// Реализованы в tsc_amd64.s
+
+// rdtscStart читает TSC после завершения предыдущих инструкций
+func rdtscStart() uint64
+
+// rdtscpEnd читает TSC инструкцией RDTSCP до начала следующих инструкций
+func rdtscpEnd() uint64
+
+// rdtscEnd — то же без RDTSCP, через LFENCE с обеих сторон
+func rdtscEnd() uint64
+
+// cpuid выполняет инструкцию CPUID
+func cpuid(leaf, subleaf uint32) (eax, ebx, ecx, edx uint32)
+
+// detectTSC проверяет, что TSC инвариантный (CPUID 0x80000007, EDX бит 8):
+// счетчик идет с постоянной частотой независимо от P- и C-состояний, и по
+// нему можно измерять время. Возвращает nil и причину, если это не так.
+func detectTSC() (*TSC, string) {
+	maxExt, _, _, _ := cpuid(0x80000000, 0)
+	if maxExt < 0x80000007 {
+		return nil, "CPUID не сообщает об инвариантном TSC"
+	}
+	if _, _, _, edx := cpuid(0x80000007, 0); edx&(1<<8) == 0 {
+		return nil, "TSC не инвариантный: частота счетчика может меняться"
+	}
+
+	t := &TSC{Invariant: true, start: rdtscStart, end: rdtscEnd}
+	// RDTSCP: CPUID 0x80000001, EDX бит 27
+	if _, _, _, edx := cpuid(0x80000001, 0); edx&(1<<27) != 0 {
+		t.RDTSCP = true
+		t.end = rdtscpEnd
+	}
+	t.calibrate()
+	return t, ""
+}
@@ -0,0 +1,44 @@
+#include "textflag.h"
+
+// Чтение TSC с упорядочиванием: LFENCE перед RDTSC не дает счетчику
+// прочитаться раньше предыдущих инструкций, LFENCE после RDTSCP — начаться
+// следующим инструкциям до чтения счетчика.
+
+// func rdtscStart() uint64
+TEXT ·rdtscStart(SB), NOSPLIT, $0-8
+	LFENCE
+	RDTSC
+	SHLQ $32, DX
+	ORQ  DX, AX
+	MOVQ AX, ret+0(FP)
+	RET
+
+// func rdtscpEnd() uint64
+TEXT ·rdtscpEnd(SB), NOSPLIT, $0-8
+	RDTSCP
+	LFENCE
+	SHLQ $32, DX
+	ORQ  DX, AX
+	MOVQ AX, ret+0(FP)
+	RET
+
+// func rdtscEnd() uint64
+TEXT ·rdtscEnd(SB), NOSPLIT, $0-8
+	LFENCE
+	RDTSC
+	LFENCE
+	SHLQ $32, DX
+	ORQ  DX, AX
+	MOVQ AX, ret+0(FP)
+	RET
+
+// func cpuid(leaf, subleaf uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL leaf+0(FP), AX
+	MOVL subleaf+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
@@ -0,0 +1,11 @@
+//go:build !amd64
+
+package main
+
+import "runtime"
+
+// detectTSC: чтение TSC реализовано только для amd64, на остальных
+// архитектурах время измеряется по CLOCK_MONOTONIC (time.Now)
+func detectTSC() (*TSC, string) {
+	return nil, "TSC недоступен на " + runtime.GOARCH
+}
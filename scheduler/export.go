@@ -0,0 +1,717 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// OutputFormat определяет формат вывода результатов
+type OutputFormat string
+
+const (
+	FormatText     OutputFormat = "text"
+	FormatCSV      OutputFormat = "csv"
+	FormatJSON     OutputFormat = "json"
+	FormatMarkdown OutputFormat = "markdown"
+)
+
+// parseOutputFormat разбирает название формата из командной строки
+func parseOutputFormat(s string) (OutputFormat, error) {
+	switch OutputFormat(strings.ToLower(s)) {
+	case FormatText, "":
+		return FormatText, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatMarkdown, "md":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("неизвестный формат вывода: %s", s)
+}
+
+// Comparison — таблица средних значений нескольких запусков планировщика
+type Comparison struct {
+	Title   string             // Название сравнения
+	Key     string             // Заголовок первого столбца (например, "Алгоритм" или "Квант")
+	Rows    []ComparisonRow    // Строки в порядке вывода
+	Columns []ComparisonColumn // Дополнительные столбцы после средних значений
+}
+
+// ComparisonColumn — дополнительный столбец сравнения, вычисляемый по результату
+type ComparisonColumn struct {
+	Name  string                           // Имя в CSV, JSON и Markdown
+	Title string                           // Заголовок в тексте
+	Value func(result SchedulerResult) int // Значение для строки
+}
+
+// ComparisonRow — одна строка сравнения
+type ComparisonRow struct {
+	Label  string
+	Result SchedulerResult
+}
+
+// resultSummary — сводка SchedulerResult без списка задач
+type resultSummary struct {
+	Label         string  `json:"label,omitempty"`
+	SchedulerType string  `json:"scheduler"`
+	TimeQuantum   int     `json:"time_quantum,omitempty"`
+	AvgResponse   float64 `json:"avg_response"`
+	AvgTurnaround float64 `json:"avg_turnaround"`
+	AvgWaiting    float64 `json:"avg_waiting"`
+	MaxWaiting    int     `json:"max_waiting"`
+	TotalTime     int     `json:"total_time"`
+
+	Columns map[string]int `json:"columns,omitempty"` // Дополнительные столбцы сравнения
+}
+
+func summarize(label string, result SchedulerResult) resultSummary {
+	return resultSummary{
+		Label:         label,
+		SchedulerType: result.SchedulerType,
+		TimeQuantum:   result.TimeQuantum,
+		AvgResponse:   result.AvgResponse,
+		AvgTurnaround: result.AvgTurnaround,
+		AvgWaiting:    result.AvgWaiting,
+		MaxWaiting:    calculateMaxWaiting(result.Tasks),
+		TotalTime:     result.TotalTime,
+	}
+}
+
+// Exporter выводит результаты в выбранном формате.
+// JSON выводится по одному объекту на строку (JSON Lines), Markdown —
+// отдельными таблицами, разделенными пустой строкой. Таблицы CSV имеют разные
+// столбцы, поэтому при заданном каталоге (SetDir) каждая записывается в
+// отдельный файл NN-раздел-таблица.csv.
+//
+// Раздел (Section) связывает результаты с анализом и нагрузкой, на которой они
+// получены: его идентификатор попадает в поле section записей JSON и в имена
+// файлов CSV, а название — в заголовок Markdown.
+type Exporter struct {
+	w       io.Writer
+	format  OutputFormat
+	tables  int    // Количество уже выведенных таблиц
+	dir     string // Каталог для файлов CSV ("" — вывод в w)
+	files   int    // Количество записанных файлов CSV
+	section string // Идентификатор текущего раздела
+}
+
+// NewExporter создает Exporter для записи в w
+func NewExporter(w io.Writer, format OutputFormat) *Exporter {
+	return &Exporter{w: w, format: format}
+}
+
+// SetDir задает каталог, в который записываются таблицы CSV, и создает его
+func (e *Exporter) SetDir(dir string) error {
+	e.dir = dir
+	return os.MkdirAll(dir, 0o755)
+}
+
+// Structured сообщает, выводятся ли результаты в машиночитаемом формате
+func (e *Exporter) Structured() bool {
+	return e.format != FormatText
+}
+
+// Section начинает раздел результатов. id — короткий идентификатор латиницей
+// (например, "sjf-vs-fifo-convoy"), title — название для человека.
+func (e *Exporter) Section(id, title string) error {
+	e.section = id
+	if e.format != FormatMarkdown {
+		return nil
+	}
+	e.separate()
+	_, err := fmt.Fprintf(e.w, "## %s\n", title)
+	return err
+}
+
+// separate отделяет очередную таблицу от предыдущей
+func (e *Exporter) separate() {
+	if e.tables > 0 && e.format == FormatMarkdown {
+		fmt.Fprintln(e.w)
+	}
+	e.tables++
+}
+
+// Result выводит результаты одного запуска планировщика
+func (e *Exporter) Result(result SchedulerResult) error {
+	e.separate()
+	switch e.format {
+	case FormatCSV:
+		return e.resultCSV(result)
+	case FormatJSON:
+		return e.writeJSON("result", result)
+	case FormatMarkdown:
+		return e.resultMarkdown(result)
+	}
+	writeResultText(e.w, result)
+	return nil
+}
+
+// Comparison выводит таблицу сравнения нескольких запусков
+func (e *Exporter) Comparison(c Comparison) error {
+	e.separate()
+	switch e.format {
+	case FormatCSV:
+		return e.comparisonCSV(c)
+	case FormatJSON:
+		rows := make([]resultSummary, len(c.Rows))
+		for i, row := range c.Rows {
+			rows[i] = summarize(row.Label, row.Result)
+			for _, column := range c.Columns {
+				if rows[i].Columns == nil {
+					rows[i].Columns = make(map[string]int)
+				}
+				rows[i].Columns[column.Name] = column.Value(row.Result)
+			}
+		}
+		return e.writeJSON("comparison", struct {
+			Title string          `json:"title"`
+			Rows  []resultSummary `json:"rows"`
+		}{c.Title, rows})
+	case FormatMarkdown:
+		return e.comparisonMarkdown(c)
+	}
+	writeComparisonText(e.w, c)
+	return nil
+}
+
+// Sweep выводит сетку результатов перебора параметра и лучшие конфигурации
+func (e *Exporter) Sweep(result SweepResult) error {
+	e.separate()
+	switch e.format {
+	case FormatCSV:
+		return e.sweepCSV(result)
+	case FormatJSON:
+		return e.writeJSON("sweep", result)
+	case FormatMarkdown:
+		return e.sweepMarkdown(result)
+	}
+	writeSweepText(e.w, result)
+	return nil
+}
+
+// RealTime выводит результаты планирования задач реального времени
+func (e *Exporter) RealTime(result RTResult) error {
+	e.separate()
+	switch e.format {
+	case FormatCSV:
+		return e.writeCSV("realtime", rtJobHeader, rtJobRows(result))
+	case FormatJSON:
+		return e.writeJSON("realtime", result)
+	case FormatMarkdown:
+		if err := e.writeMarkdown(rtTitle(result), rtJobHeader[1:], trimColumns(rtJobRows(result), 1)); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(e.w, "\n**Итого:** загрузка %.3f, нарушений сроков %d, максимальное опоздание %d\n",
+			result.Utilization, result.Misses, result.MaxLateness)
+		return err
+	}
+	writeRTResultText(e.w, result)
+	return nil
+}
+
+// Schedulability выводит результаты тестов планируемости
+func (e *Exporter) Schedulability(analysis RTAnalysis) error {
+	e.separate()
+	switch e.format {
+	case FormatCSV:
+		return e.writeCSV("schedulability", rtAnalysisHeader, rtAnalysisRows(analysis))
+	case FormatJSON:
+		return e.writeJSON("schedulability", analysis)
+	case FormatMarkdown:
+		if err := e.writeMarkdown("Анализ планируемости", rtAnalysisHeader[:4], rtAnalysisRows(analysis)); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(e.w, "\n**U** = %.3f, граница RM = %.3f, RM: %s, EDF: %s\n",
+			analysis.Utilization, analysis.RMBound, schedulableText(analysis.RMOK), schedulableText(analysis.EDFOK))
+		return err
+	}
+	writeRTAnalysisText(e.w, analysis)
+	return nil
+}
+
+// Multiprocessor выводит сравнение многопроцессорных конфигураций
+func (e *Exporter) Multiprocessor(title string, results []MPResult) error {
+	e.separate()
+	switch e.format {
+	case FormatCSV:
+		if err := e.writeCSV("multiprocessor", mpHeader, mpRows(title, results)); err != nil {
+			return err
+		}
+		return e.writeCSV("cpus", cpuHeader, cpuRows(title, results))
+	case FormatJSON:
+		return e.writeJSON("multiprocessor", struct {
+			Title   string     `json:"title"`
+			Results []MPResult `json:"results"`
+		}{title, results})
+	case FormatMarkdown:
+		if err := e.writeMarkdown(title, mpHeader[1:], trimColumns(mpRows(title, results), 1)); err != nil {
+			return err
+		}
+		fmt.Fprintln(e.w)
+		return e.writeMarkdown("", cpuHeader[1:], trimColumns(cpuRows(title, results), 1))
+	}
+	writeMultiprocessorText(e.w, title, results)
+	return nil
+}
+
+// writeJSON выводит объект вида {"type": ..., "section": ..., "data": ...} в одну строку
+func (e *Exporter) writeJSON(kind string, v interface{}) error {
+	data, err := json.Marshal(struct {
+		Type    string      `json:"type"`
+		Section string      `json:"section,omitempty"`
+		Data    interface{} `json:"data"`
+	}{kind, e.section, v})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(e.w, "%s\n", data)
+	return err
+}
+
+// writeCSV выводит таблицу в формате CSV: в w или в очередной файл каталога
+// с именем вида 03-раздел-name.csv
+func (e *Exporter) writeCSV(name string, header []string, rows [][]string) error {
+	w := e.w
+	if e.dir != "" {
+		e.files++
+		if e.section != "" {
+			name = e.section + "-" + name
+		}
+		f, err := os.Create(filepath.Join(e.dir, fmt.Sprintf("%02d-%s.csv", e.files, name)))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// writeMarkdown выводит таблицу в формате Markdown
+func (e *Exporter) writeMarkdown(title string, header []string, rows [][]string) error {
+	if title != "" {
+		if _, err := fmt.Fprintf(e.w, "### %s\n\n", title); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(e.w, "| %s |\n", strings.Join(header, " | "))
+	separators := make([]string, len(header))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	fmt.Fprintf(e.w, "| %s |\n", strings.Join(separators, " | "))
+	for _, row := range rows {
+		escaped := make([]string, len(row))
+		for i, cell := range row {
+			escaped[i] = strings.ReplaceAll(cell, "|", "\\|")
+		}
+		if _, err := fmt.Fprintf(e.w, "| %s |\n", strings.Join(escaped, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var taskHeader = []string{"scheduler", "time_quantum", "id", "arrival", "duration",
+	"start", "finish", "response", "turnaround", "waiting"}
+
+func taskRows(result SchedulerResult) [][]string {
+	rows := make([][]string, 0, len(result.Tasks))
+	for _, task := range result.Tasks {
+		rows = append(rows, []string{
+			result.SchedulerType, strconv.Itoa(result.TimeQuantum),
+			strconv.Itoa(task.ID), strconv.Itoa(task.Arrival), strconv.Itoa(task.Duration),
+			strconv.Itoa(task.Start), strconv.Itoa(task.Finish),
+			strconv.Itoa(task.Response), strconv.Itoa(task.Turnaround), strconv.Itoa(task.Waiting),
+		})
+	}
+	return rows
+}
+
+var priorityChangeHeader = []string{"scheduler", "task_id", "time", "from", "to"}
+
+func priorityChangeRows(result SchedulerResult) [][]string {
+	rows := make([][]string, 0, len(result.PriorityChanges))
+	for _, c := range result.PriorityChanges {
+		rows = append(rows, []string{
+			result.SchedulerType, strconv.Itoa(c.TaskID), strconv.Itoa(c.Time),
+			strconv.FormatFloat(c.From, 'f', -1, 64), strconv.FormatFloat(c.To, 'f', -1, 64),
+		})
+	}
+	return rows
+}
+
+func (e *Exporter) resultCSV(result SchedulerResult) error {
+	if err := e.writeCSV("tasks", taskHeader, taskRows(result)); err != nil {
+		return err
+	}
+	if len(result.PriorityChanges) == 0 {
+		return nil
+	}
+	return e.writeCSV("priority_changes", priorityChangeHeader, priorityChangeRows(result))
+}
+
+func (e *Exporter) resultMarkdown(result SchedulerResult) error {
+	if err := e.writeMarkdown(resultTitle(result), taskHeader[2:], trimColumns(taskRows(result), 2)); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(e.w, "\n**Средние значения:** отклик %s, оборотное %s, ожидание %s, общее время %d\n",
+		formatFloat(result.AvgResponse), formatFloat(result.AvgTurnaround),
+		formatFloat(result.AvgWaiting), result.TotalTime)
+	if err != nil || len(result.PriorityChanges) == 0 {
+		return err
+	}
+	fmt.Fprintln(e.w)
+	return e.writeMarkdown("Изменения приоритетов", priorityChangeHeader[1:], trimColumns(priorityChangeRows(result), 1))
+}
+
+var comparisonHeader = []string{"title", "label", "scheduler", "time_quantum",
+	"avg_response", "avg_turnaround", "avg_waiting", "max_waiting", "total_time"}
+
+// comparisonColumns возвращает заголовок сравнения с дополнительными столбцами
+func comparisonColumns(c Comparison) []string {
+	header := append([]string(nil), comparisonHeader...)
+	for _, column := range c.Columns {
+		header = append(header, column.Name)
+	}
+	return header
+}
+
+func comparisonRows(c Comparison) [][]string {
+	rows := make([][]string, 0, len(c.Rows))
+	for _, row := range c.Rows {
+		cells := []string{
+			c.Title, row.Label, row.Result.SchedulerType, strconv.Itoa(row.Result.TimeQuantum),
+			formatFloat(row.Result.AvgResponse), formatFloat(row.Result.AvgTurnaround),
+			formatFloat(row.Result.AvgWaiting), strconv.Itoa(calculateMaxWaiting(row.Result.Tasks)),
+			strconv.Itoa(row.Result.TotalTime),
+		}
+		for _, column := range c.Columns {
+			cells = append(cells, strconv.Itoa(column.Value(row.Result)))
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+func (e *Exporter) comparisonCSV(c Comparison) error {
+	return e.writeCSV("comparison", comparisonColumns(c), comparisonRows(c))
+}
+
+func (e *Exporter) comparisonMarkdown(c Comparison) error {
+	header := append([]string{c.Key}, comparisonColumns(c)[2:]...)
+	return e.writeMarkdown(c.Title, header, trimColumns(comparisonRows(c), 1))
+}
+
+func sweepGridRows(result SweepResult) [][]string {
+	var rows [][]string
+	for _, row := range result.Grid {
+		for _, cell := range row {
+			rows = append(rows, []string{
+				cell.Workload, strconv.Itoa(cell.Value),
+				formatFloat(cell.Result.AvgResponse), formatFloat(cell.Result.AvgTurnaround),
+				formatFloat(cell.Result.AvgWaiting), strconv.Itoa(cell.Result.TotalTime),
+			})
+		}
+	}
+	return rows
+}
+
+func sweepBestRows(result SweepResult) [][]string {
+	rows := make([][]string, 0, len(result.Best))
+	for _, best := range result.Best {
+		rows = append(rows, []string{best.Workload, strconv.Itoa(best.Value), formatFloat(best.Score)})
+	}
+	return rows
+}
+
+func (e *Exporter) sweepCSV(result SweepResult) error {
+	header := []string{"workload", result.Param, "avg_response", "avg_turnaround", "avg_waiting", "total_time"}
+	if err := e.writeCSV("sweep", header, sweepGridRows(result)); err != nil {
+		return err
+	}
+	return e.writeCSV("sweep_best", []string{"workload", "best_" + result.Param, result.Metric}, sweepBestRows(result))
+}
+
+func (e *Exporter) sweepMarkdown(result SweepResult) error {
+	header := append([]string{result.Param}, result.Workloads...)
+	rows := make([][]string, len(result.Values))
+	for v, value := range result.Values {
+		rows[v] = []string{strconv.Itoa(value)}
+		for w := range result.Grid {
+			score, _ := metricValue(result.Grid[w][v].Result, result.Metric)
+			rows[v] = append(rows[v], formatFloat(score))
+		}
+	}
+	title := fmt.Sprintf("Перебор %s: %s (метрика: %s)", result.Policy, result.Param, result.Metric)
+	if err := e.writeMarkdown(title, header, rows); err != nil {
+		return err
+	}
+	fmt.Fprintln(e.w)
+	return e.writeMarkdown("Лучшие конфигурации", []string{"нагрузка", result.Param, result.Metric}, sweepBestRows(result))
+}
+
+var rtJobHeader = []string{"scheduler", "task_id", "release", "deadline", "start", "finish", "lateness", "missed"}
+
+func rtJobRows(result RTResult) [][]string {
+	rows := make([][]string, 0, len(result.Jobs))
+	for _, job := range result.Jobs {
+		rows = append(rows, []string{
+			result.SchedulerType, strconv.Itoa(job.TaskID), strconv.Itoa(job.Release),
+			strconv.Itoa(job.Deadline), strconv.Itoa(job.Start), strconv.Itoa(job.Finish),
+			strconv.Itoa(job.Lateness), strconv.FormatBool(job.Missed),
+		})
+	}
+	return rows
+}
+
+var rtAnalysisHeader = []string{"task_id", "response", "deadline", "schedulable",
+	"utilization", "density", "rm_bound", "rm_bound_ok", "rm_ok", "edf_ok"}
+
+// rtAnalysisRows возвращает строки анализа; общие показатели повторяются в каждой строке
+func rtAnalysisRows(analysis RTAnalysis) [][]string {
+	rows := make([][]string, 0, len(analysis.Responses))
+	for _, r := range analysis.Responses {
+		rows = append(rows, []string{
+			strconv.Itoa(r.TaskID), strconv.Itoa(r.Response), strconv.Itoa(r.Deadline),
+			strconv.FormatBool(r.Schedulable),
+			strconv.FormatFloat(analysis.Utilization, 'f', 4, 64),
+			strconv.FormatFloat(analysis.Density, 'f', 4, 64),
+			strconv.FormatFloat(analysis.RMBound, 'f', 4, 64),
+			strconv.FormatBool(analysis.RMBoundOK), strconv.FormatBool(analysis.RMOK),
+			strconv.FormatBool(analysis.EDFOK),
+		})
+	}
+	return rows
+}
+
+func rtTitle(result RTResult) string {
+	title := fmt.Sprintf("%s (гиперпериод: %d, интервал: %d)", result.SchedulerType, result.Hyperperiod, result.Horizon)
+	if result.Truncated {
+		title += ", интервал сокращен"
+	}
+	return title
+}
+
+func schedulableText(ok bool) string {
+	if ok {
+		return "планируемо"
+	}
+	return "не гарантировано"
+}
+
+var mpHeader = []string{"title", "scheduler", "cpus", "avg_response", "avg_turnaround",
+	"avg_waiting", "total_time", "migrations", "imbalance", "cold_ticks"}
+
+func mpRows(title string, results []MPResult) [][]string {
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		rows = append(rows, []string{
+			title, r.SchedulerType, strconv.Itoa(len(r.CPUs)),
+			formatFloat(r.AvgResponse), formatFloat(r.AvgTurnaround), formatFloat(r.AvgWaiting),
+			strconv.Itoa(r.TotalTime), strconv.Itoa(r.Migrations), formatFloat(r.Imbalance),
+			strconv.Itoa(r.ColdTicks),
+		})
+	}
+	return rows
+}
+
+var cpuHeader = []string{"title", "scheduler", "cpu", "busy", "utilization", "dispatches"}
+
+func cpuRows(title string, results []MPResult) [][]string {
+	var rows [][]string
+	for _, r := range results {
+		for _, cpu := range r.CPUs {
+			rows = append(rows, []string{
+				title, r.SchedulerType, strconv.Itoa(cpu.ID), strconv.Itoa(cpu.Busy),
+				formatFloat(cpu.Utilization), strconv.Itoa(cpu.Dispatches),
+			})
+		}
+	}
+	return rows
+}
+
+// trimColumns отбрасывает первые n столбцов каждой строки
+func trimColumns(rows [][]string, n int) [][]string {
+	trimmed := make([][]string, len(rows))
+	for i, row := range rows {
+		trimmed[i] = row[n:]
+	}
+	return trimmed
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// resultTitle возвращает название запуска, например "RR (квант: 10)"
+func resultTitle(result SchedulerResult) string {
+	switch {
+	case result.TimeQuantum > 0:
+		return fmt.Sprintf("%s (квант: %d)", result.SchedulerType, result.TimeQuantum)
+	case result.AgingInterval > 0:
+		return fmt.Sprintf("%s (старение: %d)", result.SchedulerType, result.AgingInterval)
+	}
+	return result.SchedulerType
+}
+
+// writeResultText выводит результаты планирования в виде выровненного текста
+func writeResultText(w io.Writer, result SchedulerResult) {
+	fmt.Fprintf(w, "=== Результаты планирования %s ===\n", resultTitle(result))
+
+	fmt.Fprintf(w, "%-5s %-10s %-8s %-8s %-8s %-10s %-12s %-10s\n",
+		"ID", "Прибытие", "Длительн", "Начало", "Конец", "Отклик", "Оборотное", "Ожидание")
+	fmt.Fprintln(w, strings.Repeat("-", 75))
+
+	for _, task := range result.Tasks {
+		fmt.Fprintf(w, "%-5d %-10d %-8d %-8d %-8d %-10d %-12d %-10d\n",
+			task.ID, task.Arrival, task.Duration, task.Start, task.Finish,
+			task.Response, task.Turnaround, task.Waiting)
+	}
+
+	fmt.Fprintf(w, "\nСредние значения:\n")
+	fmt.Fprintf(w, "  Время отклика: %.2f\n", result.AvgResponse)
+	fmt.Fprintf(w, "  Оборотное время: %.2f\n", result.AvgTurnaround)
+	fmt.Fprintf(w, "  Время ожидания: %.2f\n", result.AvgWaiting)
+	fmt.Fprintf(w, "  Общее время: %d\n", result.TotalTime)
+
+	if len(result.PriorityChanges) > 0 {
+		fmt.Fprintf(w, "\nИзменения приоритетов:\n")
+		for _, c := range result.PriorityChanges {
+			if c.From == c.To {
+				fmt.Fprintf(w, "  t=%-6d задача %-4d %g (исходный)\n", c.Time, c.TaskID, c.From)
+				continue
+			}
+			fmt.Fprintf(w, "  t=%-6d задача %-4d %g -> %g\n", c.Time, c.TaskID, c.From, c.To)
+		}
+	}
+}
+
+// writeComparisonText выводит сравнение в виде выровненного текста
+func writeComparisonText(w io.Writer, c Comparison) {
+	fmt.Fprintf(w, "%-12s %-15s %-15s %-15s %-15s", c.Key, "Время отклика", "Оборотное время", "Время ожидания", "Макс. ожидание")
+	for _, column := range c.Columns {
+		fmt.Fprintf(w, " %-15s", column.Title)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Repeat("-", 81+16*len(c.Columns)))
+	for _, row := range c.Rows {
+		fmt.Fprintf(w, "%-12s %-15.2f %-15.2f %-15.2f %-15d",
+			row.Label, row.Result.AvgResponse, row.Result.AvgTurnaround, row.Result.AvgWaiting,
+			calculateMaxWaiting(row.Result.Tasks))
+		for _, column := range c.Columns {
+			fmt.Fprintf(w, " %-15d", column.Value(row.Result))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// writeSweepText выводит сетку перебора: строки — значения параметра,
+// столбцы — нагрузки, в ячейках — значение метрики
+func writeSweepText(w io.Writer, result SweepResult) {
+	fmt.Fprintf(w, "Перебор %s по параметру %s, метрика: %s\n", result.Policy, result.Param, result.Metric)
+	fmt.Fprintf(w, "%-10s", result.Param)
+	for _, name := range result.Workloads {
+		fmt.Fprintf(w, " %-12s", name)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Repeat("-", 10+13*len(result.Workloads)))
+	for v, value := range result.Values {
+		fmt.Fprintf(w, "%-10d", value)
+		for _, row := range result.Grid {
+			score, _ := metricValue(row[v].Result, result.Metric)
+			fmt.Fprintf(w, " %-12.2f", score)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "\nЛучшие конфигурации:\n")
+	for _, best := range result.Best {
+		fmt.Fprintf(w, "  %-12s %s=%d (%s: %.2f)\n", best.Workload, result.Param, best.Value, result.Metric, best.Score)
+	}
+}
+
+// rtTimelineLimit — сколько интервалов временной диаграммы выводится в тексте
+const rtTimelineLimit = 40
+
+// writeRTResultText выводит активации задач и временную диаграмму
+func writeRTResultText(w io.Writer, result RTResult) {
+	fmt.Fprintf(w, "=== Результаты планирования %s ===\n", rtTitle(result))
+	fmt.Fprintf(w, "%-8s %-10s %-8s %-8s %-8s %-10s %-10s\n",
+		"Задача", "Активация", "Срок", "Начало", "Конец", "Опоздание", "Нарушение")
+	fmt.Fprintln(w, strings.Repeat("-", 68))
+	for _, job := range result.Jobs {
+		missed := ""
+		if job.Missed {
+			missed = "да"
+		}
+		fmt.Fprintf(w, "%-8d %-10d %-8d %-8d %-8d %-10d %-10s\n",
+			job.TaskID, job.Release, job.Deadline, job.Start, job.Finish, job.Lateness, missed)
+	}
+
+	fmt.Fprintf(w, "\nЗагрузка: %.3f\n", result.Utilization)
+	fmt.Fprintf(w, "Нарушений сроков: %d из %d\n", result.Misses, len(result.Jobs))
+	fmt.Fprintf(w, "Максимальное опоздание: %d\n", result.MaxLateness)
+
+	fmt.Fprintf(w, "Временная диаграмма:")
+	for i, seg := range result.Timeline {
+		if i == rtTimelineLimit {
+			fmt.Fprintf(w, " ...")
+			break
+		}
+		if seg.TaskID == 0 {
+			fmt.Fprintf(w, " [%d-%d)простой", seg.Start, seg.End)
+		} else {
+			fmt.Fprintf(w, " [%d-%d)T%d", seg.Start, seg.End, seg.TaskID)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// writeRTAnalysisText выводит результаты тестов планируемости
+func writeRTAnalysisText(w io.Writer, analysis RTAnalysis) {
+	fmt.Fprintf(w, "Загрузка U = %.3f, плотность = %.3f\n", analysis.Utilization, analysis.Density)
+	bound := "пройдена"
+	if !analysis.RMBoundOK {
+		bound = "не пройдена"
+	}
+	fmt.Fprintf(w, "Граница Лю и Лейланда (n=%d): %.3f — %s\n", len(analysis.Responses), analysis.RMBound, bound)
+	fmt.Fprintf(w, "RM (анализ времени отклика): %s\n", schedulableText(analysis.RMOK))
+	fmt.Fprintf(w, "EDF (тест плотности): %s\n", schedulableText(analysis.EDFOK))
+
+	fmt.Fprintf(w, "%-8s %-10s %-8s %-10s\n", "Задача", "R (RM)", "Срок", "Успевает")
+	for _, r := range analysis.Responses {
+		ok := "да"
+		if !r.Schedulable {
+			ok = "нет"
+		}
+		fmt.Fprintf(w, "%-8d %-10d %-8d %-10s\n", r.TaskID, r.Response, r.Deadline, ok)
+	}
+}
+
+// writeMultiprocessorText выводит сводную таблицу многопроцессорных конфигураций
+func writeMultiprocessorText(w io.Writer, title string, results []MPResult) {
+	fmt.Fprintf(w, "=== %s ===\n", title)
+	fmt.Fprintf(w, "%-16s %-10s %-10s %-10s %-10s %-10s %s\n",
+		"Конфигурация", "Отклик", "Оборотное", "Миграции", "Дисбаланс", "Холодные", "Загрузка CPU")
+	fmt.Fprintln(w, strings.Repeat("-", 85))
+	for _, r := range results {
+		fmt.Fprintf(w, "%-16s %-10.2f %-10.2f %-10d %-10.2f %-10d %s\n",
+			r.SchedulerType, r.AvgResponse, r.AvgTurnaround, r.Migrations, r.Imbalance,
+			r.ColdTicks, formatUtilization(r.CPUs))
+	}
+}
@@ -1,37 +1,120 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"sort"
-	"strings"
 )
 
 // Task представляет задачу в системе
 type Task struct {
-	ID         int // Идентификатор задачи
-	Duration   int // Продолжительность выполнения
-	Arrival    int // Время прибытия
-	Start      int // Время начала выполнения
-	Finish     int // Время завершения
-	Response   int // Время отклика (Start - Arrival)
-	Turnaround int // Оборотное время (Finish - Arrival)
-	Waiting    int // Время ожидания (Turnaround - Duration)
+	ID         int `json:"id"`         // Идентификатор задачи
+	Duration   int `json:"duration"`   // Продолжительность выполнения
+	Arrival    int `json:"arrival"`    // Время прибытия
+	Start      int `json:"start"`      // Время начала выполнения
+	Finish     int `json:"finish"`     // Время завершения
+	Response   int `json:"response"`   // Время отклика (Start - Arrival)
+	Turnaround int `json:"turnaround"` // Оборотное время (Finish - Arrival)
+	Waiting    int `json:"waiting"`    // Время ожидания (Turnaround - Duration)
+	Priority   int `json:"priority"`   // Приоритет (меньшее значение — более высокий приоритет)
+
+	// Параметры задач реального времени
+	Period   int  `json:"period,omitempty"`   // Период (для спорадических — минимальный интервал между активациями)
+	Deadline int  `json:"deadline,omitempty"` // Относительный крайний срок (0 = Period)
+	WCET     int  `json:"wcet,omitempty"`     // Время выполнения в худшем случае (0 = Duration)
+	Sporadic bool `json:"sporadic,omitempty"` // Активации приходят не строго периодически
 }
 
 // SchedulerResult содержит результаты планирования
 type SchedulerResult struct {
-	Tasks         []Task
-	AvgResponse   float64
-	AvgTurnaround float64
-	AvgWaiting    float64
-	TotalTime     int
-	SchedulerType string
-	TimeQuantum   int // Для RR
+	Tasks         []Task  `json:"tasks"`
+	AvgResponse   float64 `json:"avg_response"`
+	AvgTurnaround float64 `json:"avg_turnaround"`
+	AvgWaiting    float64 `json:"avg_waiting"`
+	TotalTime     int     `json:"total_time"`
+	SchedulerType string  `json:"scheduler"`
+	TimeQuantum   int     `json:"time_quantum,omitempty"`   // Для RR
+	AgingInterval int     `json:"aging_interval,omitempty"` // Для приоритетов со старением
+
+	PriorityChanges []PriorityChange `json:"priority_changes,omitempty"` // Изменения приоритетов задач
 }
 
+var (
+	// out выводит результаты в формате, выбранном флагом -f
+	out = NewExporter(os.Stdout, FormatText)
+	// logOut получает пояснительный текст; в машиночитаемых форматах это stderr,
+	// чтобы stdout содержал только данные
+	logOut io.Writer = os.Stdout
+)
+
 func main() {
-	fmt.Println("=== Эмулятор планировщика процессов ===\n")
+	format := flag.String("f", "text", "Формат вывода результатов: text, csv, json, markdown")
+	outDir := flag.String("O", "", "Каталог для CSV: каждая таблица записывается в отдельный файл")
+	policy := flag.String("p", "rr", "Политика для перебора параметра, если в -r указан только диапазон")
+	paramRange := flag.String("r", "", "Перебор параметра: политика.параметр=диапазон или только диапазон (от:до[:шаг] или список через запятую)")
+	workers := flag.Int("P", 0, "Количество параллельных воркеров для перебора (0 = количество CPU)")
+	metric := flag.String("m", "turnaround", "Метрика для выбора лучшей конфигурации: response, turnaround, waiting")
+	workloadFile := flag.String("w", "", "JSON-файл с нагрузками для перебора (по умолчанию встроенные)")
+	flag.Parse()
+
+	outputFormat, err := parseOutputFormat(*format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		os.Exit(2)
+	}
+	out = NewExporter(os.Stdout, outputFormat)
+	if out.Structured() {
+		logOut = os.Stderr
+	}
+	// Таблицы CSV имеют разные столбцы и не складываются в один поток
+	switch {
+	case outputFormat == FormatCSV && *outDir == "":
+		fmt.Fprintln(os.Stderr, "Ошибка: для -f csv укажите каталог -O, в который таблицы запишутся отдельными файлами")
+		os.Exit(2)
+	case outputFormat != FormatCSV && *outDir != "":
+		fmt.Fprintln(os.Stderr, "Ошибка: -O используется только с -f csv")
+		os.Exit(2)
+	case *outDir != "":
+		if err := out.SetDir(*outDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	if *paramRange != "" {
+		sweep, err := parseSweepSpec(*paramRange, *policy)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			os.Exit(2)
+		}
+		workloads := defaultSweepWorkloads()
+		if *workloadFile != "" {
+			if workloads, err = loadWorkloads(*workloadFile); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+				os.Exit(2)
+			}
+		}
+		sweep.Workloads = workloads
+		sweep.Workers = *workers
+		sweep.Metric = *metric
+		result, err := runSweep(sweep)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+			os.Exit(2)
+		}
+		beginSection("sweep-"+result.Policy+"-"+result.Param,
+			fmt.Sprintf("Перебор %s: %s", result.Policy, result.Param))
+		if err := out.Sweep(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка вывода: %v\n", err)
+		}
+		return
+	}
+
+	fmt.Fprintln(logOut, "=== Эмулятор планировщика процессов ===")
+	fmt.Fprintln(logOut)
 
 	// Задачи для тестирования (продолжительность 200с)
 	tasks200 := []Task{
@@ -68,35 +151,53 @@ func main() {
 		{ID: 3, Duration: 60, Arrival: 20},
 	}
 
-	fmt.Println("1. Анализ SJF и FIFO для задач продолжительностью 200с:")
+	fmt.Fprintln(logOut, "1. Анализ SJF и FIFO для задач продолжительностью 200с:")
+	beginSection("sjf-vs-fifo", "1. SJF и FIFO, задачи продолжительностью 200с")
 	analyzeSJFvsFIFO(tasks200)
 
-	fmt.Println("\n1a. Демонстрация разницы SJF vs FIFO (эффект конвоя):")
+	fmt.Fprintln(logOut, "\n1a. Демонстрация разницы SJF vs FIFO (эффект конвоя):")
+	beginSection("sjf-vs-fifo-convoy", "1a. SJF и FIFO: эффект конвоя")
 	analyzeSJFvsFIFO(tasksDemonstration)
 
-	fmt.Println("\n2. Анализ различных продолжительностей:")
-	fmt.Println("\n--- 100с общая продолжительность ---")
+	fmt.Fprintln(logOut, "\n2. Анализ различных продолжительностей:")
+	fmt.Fprintln(logOut, "\n--- 100с общая продолжительность ---")
+	beginSection("duration-100", "2. SJF и FIFO, общая продолжительность 100с")
 	analyzeSJFvsFIFO(tasks100)
-	fmt.Println("\n--- 200с общая продолжительность ---")
+	fmt.Fprintln(logOut, "\n--- 200с общая продолжительность ---")
+	beginSection("duration-200", "2. SJF и FIFO, общая продолжительность 200с")
 	analyzeSJFvsFIFO(tasks200)
-	fmt.Println("\n--- 300с общая продолжительность ---")
+	fmt.Fprintln(logOut, "\n--- 300с общая продолжительность ---")
+	beginSection("duration-300", "2. SJF и FIFO, общая продолжительность 300с")
 	analyzeSJFvsFIFO(tasks300)
 
-	fmt.Println("\n3. Анализ RR с временным квантом 1:")
+	fmt.Fprintln(logOut, "\n3. Анализ RR с временным квантом 1:")
+	beginSection("rr-quantum-1", "3. RR с временным квантом 1")
 	resultRR := scheduleRR(tasksRR, 1)
 	printResult(resultRR)
 
-	fmt.Println("\n4. Сравнение всех алгоритмов:")
+	fmt.Fprintln(logOut, "\n4. Сравнение всех алгоритмов:")
+	beginSection("all-schedulers", "4. Сравнение всех алгоритмов")
 	compareAllSchedulers(tasks200)
 
-	fmt.Println("\n5. Анализ рабочих нагрузок:")
+	fmt.Fprintln(logOut, "\n5. Анализ рабочих нагрузок:")
 	analyzeWorkloads()
 
-	fmt.Println("\n6. Анализ RR с увеличением временного кванта:")
+	fmt.Fprintln(logOut, "\n6. Анализ RR с увеличением временного кванта:")
+	beginSection("rr-quantum", "6. Влияние кванта на RR")
 	analyzeRRTimeQuantum()
 
-	fmt.Println("\n7. Формула времени отклика для RR:")
+	fmt.Fprintln(logOut, "\n7. Формула времени отклика для RR:")
 	deriveRRResponseTimeFormula()
+
+	fmt.Fprintln(logOut, "\n8. Планирование реального времени (EDF и RM):")
+	analyzeRealTime()
+
+	fmt.Fprintln(logOut, "\n9. Приоритеты, старение и HRRN против голодания:")
+	beginSection("starvation", "9. Приоритеты, старение и HRRN против голодания")
+	analyzeStarvation()
+
+	fmt.Fprintln(logOut, "\n10. Многопроцессорное планирование (SQMS и MQMS):")
+	analyzeMultiprocessor()
 }
 
 // scheduleFIFO реализует планирование FIFO (First In, First Out)
@@ -335,29 +436,25 @@ func calculateAvgWaiting(tasks []Task) float64 {
 	return float64(total) / float64(len(tasks))
 }
 
+// beginSection начинает раздел результатов, к которому относится дальнейший вывод
+func beginSection(id, title string) {
+	if err := out.Section(id, title); err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка вывода: %v\n", err)
+	}
+}
+
 // printResult выводит результаты планирования
 func printResult(result SchedulerResult) {
-	fmt.Printf("=== Результаты планирования %s", result.SchedulerType)
-	if result.TimeQuantum > 0 {
-		fmt.Printf(" (квант: %d)", result.TimeQuantum)
+	if err := out.Result(result); err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка вывода: %v\n", err)
 	}
-	fmt.Println(" ===")
-
-	fmt.Printf("%-5s %-10s %-8s %-8s %-8s %-10s %-12s %-10s\n",
-		"ID", "Прибытие", "Длительн", "Начало", "Конец", "Отклик", "Оборотное", "Ожидание")
-	fmt.Println(strings.Repeat("-", 75))
+}
 
-	for _, task := range result.Tasks {
-		fmt.Printf("%-5d %-10d %-8d %-8d %-8d %-10d %-12d %-10d\n",
-			task.ID, task.Arrival, task.Duration, task.Start, task.Finish,
-			task.Response, task.Turnaround, task.Waiting)
+// printComparison выводит таблицу сравнения
+func printComparison(c Comparison) {
+	if err := out.Comparison(c); err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка вывода: %v\n", err)
 	}
-
-	fmt.Printf("\nСредние значения:\n")
-	fmt.Printf("  Время отклика: %.2f\n", result.AvgResponse)
-	fmt.Printf("  Оборотное время: %.2f\n", result.AvgTurnaround)
-	fmt.Printf("  Время ожидания: %.2f\n", result.AvgWaiting)
-	fmt.Printf("  Общее время: %d\n", result.TotalTime)
 }
 
 // analyzeSJFvsFIFO сравнивает SJF и FIFO
@@ -366,14 +463,14 @@ func analyzeSJFvsFIFO(tasks []Task) {
 	resultFIFO := scheduleFIFO(tasks)
 
 	printResult(resultSJF)
-	fmt.Println()
+	fmt.Fprintln(logOut)
 	printResult(resultFIFO)
 
-	fmt.Printf("\n--- Сравнение SJF vs FIFO ---\n")
-	fmt.Printf("Время отклика - SJF: %.2f, FIFO: %.2f (разница: %.2f)\n",
+	fmt.Fprintf(logOut, "\n--- Сравнение SJF vs FIFO ---\n")
+	fmt.Fprintf(logOut, "Время отклика - SJF: %.2f, FIFO: %.2f (разница: %.2f)\n",
 		resultSJF.AvgResponse, resultFIFO.AvgResponse,
 		resultFIFO.AvgResponse-resultSJF.AvgResponse)
-	fmt.Printf("Оборотное время - SJF: %.2f, FIFO: %.2f (разница: %.2f)\n",
+	fmt.Fprintf(logOut, "Оборотное время - SJF: %.2f, FIFO: %.2f (разница: %.2f)\n",
 		resultSJF.AvgTurnaround, resultFIFO.AvgTurnaround,
 		resultFIFO.AvgTurnaround-resultSJF.AvgTurnaround)
 }
@@ -385,12 +482,16 @@ func compareAllSchedulers(tasks []Task) {
 	resultRR1 := scheduleRR(tasks, 1)
 	resultRR10 := scheduleRR(tasks, 10)
 
-	fmt.Printf("%-12s %-15s %-15s %-15s\n", "Алгоритм", "Время отклика", "Оборотное время", "Время ожидания")
-	fmt.Println(strings.Repeat("-", 65))
-	fmt.Printf("%-12s %-15.2f %-15.2f %-15.2f\n", "SJF", resultSJF.AvgResponse, resultSJF.AvgTurnaround, resultSJF.AvgWaiting)
-	fmt.Printf("%-12s %-15.2f %-15.2f %-15.2f\n", "FIFO", resultFIFO.AvgResponse, resultFIFO.AvgTurnaround, resultFIFO.AvgWaiting)
-	fmt.Printf("%-12s %-15.2f %-15.2f %-15.2f\n", "RR(q=1)", resultRR1.AvgResponse, resultRR1.AvgTurnaround, resultRR1.AvgWaiting)
-	fmt.Printf("%-12s %-15.2f %-15.2f %-15.2f\n", "RR(q=10)", resultRR10.AvgResponse, resultRR10.AvgTurnaround, resultRR10.AvgWaiting)
+	printComparison(Comparison{
+		Title: "Сравнение всех алгоритмов",
+		Key:   "Алгоритм",
+		Rows: []ComparisonRow{
+			{"SJF", resultSJF},
+			{"FIFO", resultFIFO},
+			{"RR(q=1)", resultRR1},
+			{"RR(q=10)", resultRR10},
+		},
+	})
 }
 
 // analyzeWorkloads анализирует разные типы рабочих нагрузок
@@ -409,10 +510,12 @@ func analyzeWorkloads() {
 		{ID: 3, Duration: 100, Arrival: 20},
 	}
 
-	fmt.Println("Анализ коротких задач (длительность 1с):")
+	fmt.Fprintln(logOut, "Анализ коротких задач (длительность 1с):")
+	beginSection("workload-short", "5. SJF и FIFO, короткие задачи (длительность 1с)")
 	analyzeSJFvsFIFO(shortTasks)
 
-	fmt.Println("\nАнализ длинных задач (длительность 100с):")
+	fmt.Fprintln(logOut, "\nАнализ длинных задач (длительность 100с):")
+	beginSection("workload-long", "5. SJF и FIFO, длинные задачи (длительность 100с)")
 	analyzeSJFvsFIFO(longTasks)
 }
 
@@ -426,29 +529,36 @@ func analyzeRRTimeQuantum() {
 
 	quantums := []int{1, 5, 10, 20, 50}
 
-	fmt.Printf("%-10s %-15s %-15s %-15s\n", "Квант", "Время отклика", "Оборотное время", "Время ожидания")
-	fmt.Println(strings.Repeat("-", 60))
+	sweep, err := runSweep(SweepConfig{
+		Policy:    "rr",
+		Values:    quantums,
+		Workloads: []Workload{{Name: "равные", Tasks: tasks}},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		return
+	}
 
-	for _, quantum := range quantums {
-		result := scheduleRR(tasks, quantum)
-		fmt.Printf("%-10d %-15.2f %-15.2f %-15.2f\n",
-			quantum, result.AvgResponse, result.AvgTurnaround, result.AvgWaiting)
+	comparison := Comparison{Title: "Влияние кванта на RR", Key: "Квант"}
+	for _, cell := range sweep.Grid[0] {
+		comparison.Rows = append(comparison.Rows, ComparisonRow{fmt.Sprintf("%d", cell.Value), cell.Result})
 	}
+	printComparison(comparison)
 }
 
 // deriveRRResponseTimeFormula выводит формулу времени отклика для RR
 func deriveRRResponseTimeFormula() {
-	fmt.Println("Формула времени отклика в худшем случае для RR:")
-	fmt.Println("Если есть N задач, каждая требует времени T, и квант времени равен Q:")
-	fmt.Println("")
-	fmt.Println("Время отклика = (N-1) * Q")
-	fmt.Println("")
-	fmt.Println("Объяснение:")
-	fmt.Println("- Задача может ждать максимум (N-1) полных квантов времени")
-	fmt.Println("- до того, как получит свой первый квант времени")
-	fmt.Println("- Это происходит, когда задача прибывает последней")
-	fmt.Println("- и все остальные задачи уже находятся в очереди")
-	fmt.Println("")
-	fmt.Println("Пример с 3 задачами и квантом 1:")
-	fmt.Println("Максимальное время отклика = (3-1) * 1 = 2 единицы времени")
+	fmt.Fprintln(logOut, "Формула времени отклика в худшем случае для RR:")
+	fmt.Fprintln(logOut, "Если есть N задач, каждая требует времени T, и квант времени равен Q:")
+	fmt.Fprintln(logOut)
+	fmt.Fprintln(logOut, "Время отклика = (N-1) * Q")
+	fmt.Fprintln(logOut)
+	fmt.Fprintln(logOut, "Объяснение:")
+	fmt.Fprintln(logOut, "- Задача может ждать максимум (N-1) полных квантов времени")
+	fmt.Fprintln(logOut, "- до того, как получит свой первый квант времени")
+	fmt.Fprintln(logOut, "- Это происходит, когда задача прибывает последней")
+	fmt.Fprintln(logOut, "- и все остальные задачи уже находятся в очереди")
+	fmt.Fprintln(logOut)
+	fmt.Fprintln(logOut, "Пример с 3 задачами и квантом 1:")
+	fmt.Fprintln(logOut, "Максимальное время отклика = (3-1) * 1 = 2 единицы времени")
 }
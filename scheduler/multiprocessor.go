@@ -0,0 +1,335 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MPMode определяет организацию очередей многопроцессорного планировщика
+type MPMode string
+
+const (
+	MPSingleQueue MPMode = "SQMS" // Одна общая очередь для всех CPU
+	MPMultiQueue  MPMode = "MQMS" // Отдельная очередь на каждом CPU
+)
+
+// MPConfig задает параметры многопроцессорного планирования
+type MPConfig struct {
+	CPUs        int    // Количество процессоров
+	Mode        MPMode // SQMS или MQMS
+	TimeQuantum int    // Квант RR на каждом CPU
+	Affinity    bool   // SQMS: CPU предпочитает задачи, которые последними выполнялись на нем
+	Stealing    bool   // MQMS: простаивающий CPU забирает задачу из самой длинной чужой очереди
+	CacheWarmup int    // Тактов прогрева кэша: на "холодном" CPU задача выполняется вдвое медленнее
+}
+
+// CPUStats содержит статистику одного процессора
+type CPUStats struct {
+	ID          int     `json:"id"`
+	Busy        int     `json:"busy"` // Тактов выполнения задач
+	Utilization float64 `json:"utilization"`
+	Dispatches  int     `json:"dispatches"` // Количество запусков задач
+}
+
+// MPResult содержит результаты многопроцессорного планирования
+type MPResult struct {
+	SchedulerResult
+	CPUs       []CPUStats `json:"cpus"`
+	Migrations int        `json:"migrations"` // Запусков задачи не на том CPU, где она выполнялась последней
+	Imbalance  float64    `json:"imbalance"`  // (max - min) / среднее время занятости CPU
+	ColdTicks  int        `json:"cold_ticks"` // Тактов выполнения с холодным кэшем
+}
+
+// mpName возвращает название конфигурации, например "MQMS+steal"
+func mpName(cfg MPConfig) string {
+	name := string(cfg.Mode)
+	if cfg.Mode == MPSingleQueue && cfg.Affinity {
+		name += "+affinity"
+	}
+	if cfg.Mode == MPMultiQueue && cfg.Stealing {
+		name += "+steal"
+	}
+	return name
+}
+
+// scheduleMultiprocessor моделирует RR на нескольких CPU по одной единице времени.
+//
+// Модель кэша: состояние кэша задачи находится на CPU, где она выполнялась
+// последней. После переноса на другой CPU первые CacheWarmup тактов задача
+// выполняет только половину единицы работы за такт.
+func scheduleMultiprocessor(tasks []Task, cfg MPConfig) MPResult {
+	type TaskState struct {
+		Task
+		Remaining int // Оставшаяся работа в половинах единицы времени
+		LastCPU   int // -1, если задача еще не выполнялась
+		Cold      int // Оставшиеся такты прогрева кэша на текущем CPU
+		Started   bool
+	}
+	type CPUState struct {
+		Queue     []int // MQMS: индексы задач в локальной очереди
+		Running   int   // -1, если CPU простаивает
+		SliceLeft int
+		Stats     CPUStats
+	}
+
+	if cfg.CPUs < 1 {
+		cfg.CPUs = 1
+	}
+	if cfg.TimeQuantum < 1 {
+		cfg.TimeQuantum = 1
+	}
+
+	states := make([]TaskState, len(tasks))
+	for i, task := range tasks {
+		states[i] = TaskState{Task: task, Remaining: 2 * task.Duration, LastCPU: -1}
+	}
+	cpus := make([]CPUState, cfg.CPUs)
+	for i := range cpus {
+		cpus[i].Running = -1
+		cpus[i].Stats.ID = i
+	}
+
+	var globalQueue []int // SQMS: общая очередь
+	var preempted []int   // Задачи, у которых истек квант на прошлом такте
+	var completed []Task
+	migrations, coldTicks := 0, 0
+	arrived := make([]bool, len(tasks))
+	currentTime := 0
+
+	// enqueue ставит задачу в очередь: общую или локальную очередь CPU
+	enqueue := func(idx, cpu int) {
+		if cfg.Mode == MPSingleQueue {
+			globalQueue = append(globalQueue, idx)
+		} else {
+			cpus[cpu].Queue = append(cpus[cpu].Queue, idx)
+		}
+	}
+
+	// shortestQueue выбирает CPU с наименьшим количеством задач
+	shortestQueue := func() int {
+		best, bestLoad := 0, -1
+		for i := range cpus {
+			load := len(cpus[i].Queue)
+			if cpus[i].Running >= 0 {
+				load++
+			}
+			if bestLoad == -1 || load < bestLoad {
+				best, bestLoad = i, load
+			}
+		}
+		return best
+	}
+
+	for len(completed) < len(tasks) {
+		// Новые задачи прибывают раньше, чем возвращаются вытесненные (как в scheduleRR)
+		for i := range states {
+			if !arrived[i] && states[i].Arrival <= currentTime {
+				arrived[i] = true
+				enqueue(i, shortestQueue())
+			}
+		}
+		for _, idx := range preempted {
+			enqueue(idx, states[idx].LastCPU)
+		}
+		preempted = preempted[:0]
+
+		// Свободные CPU выбирают задачи
+		for c := range cpus {
+			cpu := &cpus[c]
+			if cpu.Running >= 0 {
+				continue
+			}
+
+			next := -1
+			switch cfg.Mode {
+			case MPSingleQueue:
+				pos := -1
+				if cfg.Affinity {
+					for i, idx := range globalQueue {
+						if states[idx].LastCPU == c {
+							pos = i
+							break
+						}
+					}
+				}
+				if pos == -1 && len(globalQueue) > 0 {
+					pos = 0
+				}
+				if pos >= 0 {
+					next = globalQueue[pos]
+					globalQueue = append(globalQueue[:pos], globalQueue[pos+1:]...)
+				}
+			case MPMultiQueue:
+				if len(cpu.Queue) > 0 {
+					next = cpu.Queue[0]
+					cpu.Queue = cpu.Queue[1:]
+				} else if cfg.Stealing {
+					// Забираем последнюю задачу из самой длинной очереди
+					victim := -1
+					for v := range cpus {
+						if v != c && len(cpus[v].Queue) > 0 && (victim == -1 || len(cpus[v].Queue) > len(cpus[victim].Queue)) {
+							victim = v
+						}
+					}
+					if victim >= 0 {
+						q := cpus[victim].Queue
+						next = q[len(q)-1]
+						cpus[victim].Queue = q[:len(q)-1]
+					}
+				}
+			}
+			if next == -1 {
+				continue
+			}
+
+			task := &states[next]
+			if !task.Started {
+				task.Start = currentTime
+				task.Started = true
+			}
+			if task.LastCPU != c {
+				if task.LastCPU >= 0 {
+					migrations++
+				}
+				task.Cold = cfg.CacheWarmup
+			}
+			task.LastCPU = c
+			cpu.Running = next
+			cpu.SliceLeft = cfg.TimeQuantum
+			cpu.Stats.Dispatches++
+		}
+
+		// Проверяем, есть ли работа; если нет — переходим к следующему прибытию
+		busy := false
+		for c := range cpus {
+			if cpus[c].Running >= 0 {
+				busy = true
+			}
+		}
+		if !busy {
+			nextArrival := -1
+			for i := range states {
+				if !arrived[i] && (nextArrival == -1 || states[i].Arrival < nextArrival) {
+					nextArrival = states[i].Arrival
+				}
+			}
+			if nextArrival == -1 {
+				break
+			}
+			currentTime = nextArrival
+			continue
+		}
+
+		// Выполняем один такт на каждом занятом CPU
+		currentTime++
+		for c := range cpus {
+			cpu := &cpus[c]
+			if cpu.Running < 0 {
+				continue
+			}
+			task := &states[cpu.Running]
+			cpu.Stats.Busy++
+			cpu.SliceLeft--
+			if task.Cold > 0 {
+				task.Cold--
+				task.Remaining--
+				coldTicks++
+			} else {
+				task.Remaining -= 2
+			}
+
+			if task.Remaining <= 0 {
+				task.Finish = currentTime
+				task.Response = task.Start - task.Arrival
+				task.Turnaround = task.Finish - task.Arrival
+				task.Waiting = task.Turnaround - task.Duration
+				completed = append(completed, task.Task)
+				cpu.Running = -1
+			} else if cpu.SliceLeft == 0 {
+				preempted = append(preempted, cpu.Running)
+				cpu.Running = -1
+			}
+		}
+	}
+
+	result := MPResult{
+		SchedulerResult: SchedulerResult{
+			Tasks:         completed,
+			SchedulerType: mpName(cfg),
+			TimeQuantum:   cfg.TimeQuantum,
+			TotalTime:     currentTime,
+			AvgResponse:   calculateAvgResponse(completed),
+			AvgTurnaround: calculateAvgTurnaround(completed),
+			AvgWaiting:    calculateAvgWaiting(completed),
+		},
+		Migrations: migrations,
+		ColdTicks:  coldTicks,
+	}
+
+	minBusy, maxBusy, totalBusy := -1, 0, 0
+	for _, cpu := range cpus {
+		stats := cpu.Stats
+		if currentTime > 0 {
+			stats.Utilization = float64(stats.Busy) / float64(currentTime)
+		}
+		result.CPUs = append(result.CPUs, stats)
+		totalBusy += stats.Busy
+		maxBusy = max(maxBusy, stats.Busy)
+		if minBusy == -1 || stats.Busy < minBusy {
+			minBusy = stats.Busy
+		}
+	}
+	if totalBusy > 0 {
+		result.Imbalance = float64(maxBusy-minBusy) / (float64(totalBusy) / float64(len(cpus)))
+	}
+	return result
+}
+
+// printMultiprocessor выводит сравнение многопроцессорных конфигураций
+func printMultiprocessor(title string, results []MPResult) {
+	if err := out.Multiprocessor(title, results); err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка вывода: %v\n", err)
+	}
+}
+
+// analyzeMultiprocessor сравнивает SQMS и MQMS с учетом привязки к кэшу
+func analyzeMultiprocessor() {
+	// Смесь длинных и коротких задач, приходящих волнами
+	var tasks []Task
+	for i := 0; i < 10; i++ {
+		duration := 20
+		if i%3 == 0 {
+			duration = 60
+		}
+		tasks = append(tasks, Task{ID: i + 1, Duration: duration, Arrival: (i / 4) * 15})
+	}
+
+	for _, cpus := range []int{2, 4} {
+		configs := []MPConfig{
+			{Mode: MPSingleQueue},
+			{Mode: MPSingleQueue, Affinity: true},
+			{Mode: MPMultiQueue},
+			{Mode: MPMultiQueue, Stealing: true},
+		}
+		var results []MPResult
+		for _, cfg := range configs {
+			cfg.CPUs = cpus
+			cfg.TimeQuantum = 5
+			cfg.CacheWarmup = 4
+			results = append(results, scheduleMultiprocessor(tasks, cfg))
+		}
+		fmt.Fprintln(logOut)
+		beginSection(fmt.Sprintf("multiprocessor-%dcpu", cpus), fmt.Sprintf("10. Многопроцессорное планирование: %d CPU", cpus))
+		printMultiprocessor(fmt.Sprintf("%d CPU, квант 5, прогрев кэша 4", cpus), results)
+	}
+}
+
+// formatUtilization возвращает загрузку CPU в виде "0.95/0.80"
+func formatUtilization(cpus []CPUStats) string {
+	parts := make([]string, len(cpus))
+	for i, cpu := range cpus {
+		parts[i] = fmt.Sprintf("%.2f", cpu.Utilization)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// PriorityChange фиксирует изменение приоритета задачи во время планирования.
+// Для приоритетных политик это номер приоритета (меньше — выше), для HRRN —
+// коэффициент отклика (больше — выше). При прибытии задачи приоритетные
+// политики записывают ее исходный приоритет (From == To), поэтому приоритет
+// до и после виден и у задач, приоритет которых не менялся.
+type PriorityChange struct {
+	TaskID int     `json:"task_id"`
+	Time   int     `json:"time"`
+	From   float64 `json:"from"`
+	To     float64 `json:"to"`
+}
+
+// schedulePriority реализует планирование по статическому приоритету
+func schedulePriority(tasks []Task, preemptive bool) SchedulerResult {
+	name := "PRIO"
+	if preemptive {
+		name = "PRIO-P"
+	}
+	return runPriority(tasks, name, preemptive, 0)
+}
+
+// schedulePriorityAging реализует вытесняющее приоритетное планирование со
+// старением: каждые agingInterval единиц ожидания приоритет задачи повышается
+// на единицу, а после потери процессора возвращается к исходному
+func schedulePriorityAging(tasks []Task, agingInterval int) SchedulerResult {
+	result := runPriority(tasks, "PRIO+AGING", true, agingInterval)
+	result.AgingInterval = agingInterval
+	return result
+}
+
+// runPriority моделирует приоритетное планирование по одной единице времени
+func runPriority(tasks []Task, name string, preemptive bool, agingInterval int) SchedulerResult {
+	type TaskState struct {
+		Task
+		RemainingTime int
+		Effective     int // Текущий (с учетом старения) приоритет
+		Waited        int // Время ожидания с последнего повышения приоритета
+		Arrived       bool
+		Started       bool
+	}
+
+	states := make([]TaskState, len(tasks))
+	for i, task := range tasks {
+		states[i] = TaskState{Task: task, RemainingTime: task.Duration, Effective: task.Priority}
+	}
+
+	var completed []Task
+	var changes []PriorityChange
+	currentTime := 0
+	running := -1
+
+	// restore возвращает задаче исходный приоритет после потери процессора
+	restore := func(i int) {
+		s := &states[i]
+		if s.Effective != s.Priority {
+			changes = append(changes, PriorityChange{s.ID, currentTime, float64(s.Effective), float64(s.Priority)})
+			s.Effective = s.Priority
+		}
+		s.Waited = 0
+	}
+
+	for len(completed) < len(tasks) {
+		for i := range states {
+			s := &states[i]
+			if !s.Arrived && s.Arrival <= currentTime {
+				s.Arrived = true
+				changes = append(changes, PriorityChange{s.ID, s.Arrival, float64(s.Priority), float64(s.Priority)})
+				// Задача нулевой длительности завершается в момент прибытия
+				if s.RemainingTime == 0 {
+					s.Start, s.Finish = s.Arrival, s.Arrival
+					s.Response, s.Turnaround, s.Waiting = 0, 0, 0
+					completed = append(completed, s.Task)
+				}
+			}
+		}
+		if len(completed) == len(tasks) {
+			break
+		}
+
+		// Выбираем задачу: без вытеснения текущая выполняется до конца
+		next := -1
+		if !preemptive && running >= 0 {
+			next = running
+		} else {
+			for i := range states {
+				s := &states[i]
+				if s.Arrival > currentTime || s.RemainingTime == 0 {
+					continue
+				}
+				if next == -1 || s.Effective < states[next].Effective ||
+					(s.Effective == states[next].Effective && (s.Arrival < states[next].Arrival ||
+						(s.Arrival == states[next].Arrival && s.ID < states[next].ID))) {
+					next = i
+				}
+			}
+		}
+
+		if next == -1 {
+			// Нет доступных задач, переходим к следующему времени прибытия
+			minArrival := math.MaxInt32
+			for _, s := range states {
+				if (s.RemainingTime > 0 || !s.Arrived) && s.Arrival < minArrival {
+					minArrival = s.Arrival
+				}
+			}
+			currentTime = minArrival
+			continue
+		}
+
+		if running >= 0 && running != next {
+			restore(running)
+		}
+		running = next
+
+		task := &states[running]
+		if !task.Started {
+			task.Start = currentTime
+			task.Started = true
+		}
+
+		// Старение ожидающих задач
+		for i := range states {
+			s := &states[i]
+			if i == running || s.Arrival > currentTime || s.RemainingTime == 0 {
+				continue
+			}
+			s.Waited++
+			if agingInterval > 0 && s.Waited >= agingInterval && s.Effective > 0 {
+				changes = append(changes, PriorityChange{s.ID, currentTime + 1, float64(s.Effective), float64(s.Effective - 1)})
+				s.Effective--
+				s.Waited = 0
+			}
+		}
+
+		currentTime++
+		task.RemainingTime--
+
+		if task.RemainingTime == 0 {
+			restore(running)
+			task.Finish = currentTime
+			task.Response = task.Start - task.Arrival
+			task.Turnaround = task.Finish - task.Arrival
+			task.Waiting = task.Turnaround - task.Duration
+			completed = append(completed, task.Task)
+			running = -1
+		}
+	}
+
+	return SchedulerResult{
+		Tasks:           completed,
+		SchedulerType:   name,
+		TotalTime:       currentTime,
+		AvgResponse:     calculateAvgResponse(completed),
+		AvgTurnaround:   calculateAvgTurnaround(completed),
+		AvgWaiting:      calculateAvgWaiting(completed),
+		PriorityChanges: changes,
+	}
+}
+
+// scheduleHRRN реализует планирование Highest Response Ratio Next:
+// без вытеснения выбирается задача с наибольшим (ожидание + длительность) / длительность
+func scheduleHRRN(tasks []Task) SchedulerResult {
+	result := make([]Task, len(tasks))
+	copy(result, tasks)
+
+	var completed []Task
+	var changes []PriorityChange
+	currentTime := 0
+
+	ratio := func(task Task) float64 {
+		if task.Duration == 0 {
+			return math.Inf(1)
+		}
+		return float64(currentTime-task.Arrival+task.Duration) / float64(task.Duration)
+	}
+
+	for len(result) > 0 {
+		best := -1
+		for i, task := range result {
+			if task.Arrival > currentTime {
+				continue
+			}
+			if best == -1 || ratio(task) > ratio(result[best]) {
+				best = i
+			}
+		}
+
+		if best == -1 {
+			minArrival := math.MaxInt32
+			for _, task := range result {
+				if task.Arrival < minArrival {
+					minArrival = task.Arrival
+				}
+			}
+			currentTime = minArrival
+			continue
+		}
+
+		// Коэффициент отклика растет с 1 в момент прибытия до значения при запуске
+		task := result[best]
+		changes = append(changes, PriorityChange{task.ID, currentTime, 1, math.Round(ratio(task)*100) / 100})
+		task.Start = currentTime
+		task.Finish = currentTime + task.Duration
+		task.Response = task.Start - task.Arrival
+		task.Turnaround = task.Finish - task.Arrival
+		task.Waiting = task.Turnaround - task.Duration
+
+		completed = append(completed, task)
+		currentTime = task.Finish
+		result = append(result[:best], result[best+1:]...)
+	}
+
+	return SchedulerResult{
+		Tasks:           completed,
+		SchedulerType:   "HRRN",
+		TotalTime:       currentTime,
+		AvgResponse:     calculateAvgResponse(completed),
+		AvgTurnaround:   calculateAvgTurnaround(completed),
+		AvgWaiting:      calculateAvgWaiting(completed),
+		PriorityChanges: changes,
+	}
+}
+
+// calculateMaxWaiting возвращает наибольшее время ожидания — оценку голодания
+func calculateMaxWaiting(tasks []Task) int {
+	maxWaiting := 0
+	for _, task := range tasks {
+		if task.Waiting > maxWaiting {
+			maxWaiting = task.Waiting
+		}
+	}
+	return maxWaiting
+}
+
+// analyzeStarvation сравнивает, как старение и HRRN ограничивают голодание
+// длинной низкоприоритетной задачи по сравнению с SJF
+func analyzeStarvation() {
+	// Длинная задача с низким приоритетом и поток коротких приоритетных задач
+	tasks := []Task{{ID: 1, Duration: 40, Arrival: 1, Priority: 5}}
+	for i := 0; i < 30; i++ {
+		tasks = append(tasks, Task{ID: i + 2, Duration: 5, Arrival: i * 5, Priority: 1})
+	}
+
+	results := []ComparisonRow{
+		{"SJF", scheduleSJF(tasks)},
+		{"PRIO", schedulePriority(tasks, false)},
+		{"PRIO-P", schedulePriority(tasks, true)},
+		{"AGING(5)", schedulePriorityAging(tasks, 5)},
+		{"AGING(20)", schedulePriorityAging(tasks, 20)},
+		{"HRRN", scheduleHRRN(tasks)},
+	}
+	printComparison(Comparison{
+		Title: "Голодание: SJF, приоритеты, старение и HRRN",
+		Key:   "Алгоритм",
+		Rows:  results,
+		Columns: []ComparisonColumn{
+			{Name: "task1_waiting", Title: "Ожидание T1", Value: func(result SchedulerResult) int {
+				for _, task := range result.Tasks {
+					if task.ID == 1 {
+						return task.Waiting
+					}
+				}
+				return 0
+			}},
+			{Name: "priority_changes", Title: "Изменения", Value: func(result SchedulerResult) int {
+				changes := 0
+				for _, c := range result.PriorityChanges {
+					if c.From != c.To {
+						changes++
+					}
+				}
+				return changes
+			}},
+		},
+	})
+
+	fmt.Fprintln(logOut)
+	printResult(results[3].Result)
+}
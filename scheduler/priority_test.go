@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+// TestPriorityZeroDuration проверяет, что задача нулевой длительности
+// завершается в момент прибытия и не останавливает планирование
+func TestPriorityZeroDuration(t *testing.T) {
+	tasks := []Task{
+		{ID: 1, Duration: 3, Arrival: 0, Priority: 1},
+		{ID: 2, Duration: 0, Arrival: 1, Priority: 0},
+		{ID: 3, Duration: 0, Arrival: 10, Priority: 2},
+	}
+	for _, result := range []SchedulerResult{
+		schedulePriority(tasks, false),
+		schedulePriority(tasks, true),
+		schedulePriorityAging(tasks, 1),
+	} {
+		if len(result.Tasks) != len(tasks) {
+			t.Fatalf("%s: завершено %d задач из %d", result.SchedulerType, len(result.Tasks), len(tasks))
+		}
+		for _, task := range result.Tasks {
+			if task.Duration == 0 && (task.Start != task.Arrival || task.Finish != task.Arrival || task.Waiting != 0) {
+				t.Errorf("%s: задача %d: начало %d, конец %d, ожидание %d, ожидается %d, %d, 0",
+					result.SchedulerType, task.ID, task.Start, task.Finish, task.Waiting, task.Arrival, task.Arrival)
+			}
+		}
+		if result.TotalTime != 10 {
+			t.Errorf("%s: общее время %d, ожидается 10", result.SchedulerType, result.TotalTime)
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+)
+
+// rtMaxHorizon ограничивает длину симуляции, если гиперпериод слишком велик
+const rtMaxHorizon = 1000000
+
+// RTJob — одна активация задачи реального времени
+type RTJob struct {
+	TaskID   int  `json:"task_id"`
+	Release  int  `json:"release"`  // Время активации
+	Deadline int  `json:"deadline"` // Абсолютный крайний срок
+	Start    int  `json:"start"`    // -1, если задача не начала выполняться
+	Finish   int  `json:"finish"`   // -1, если задача не завершилась до конца симуляции
+	Lateness int  `json:"lateness"` // Finish - Deadline (отрицательное значение — запас)
+	Missed   bool `json:"missed"`   // Крайний срок нарушен
+}
+
+// RTSegment — непрерывный интервал выполнения одной задачи
+type RTSegment struct {
+	TaskID int `json:"task_id"` // 0 — процессор простаивает
+	Start  int `json:"start"`
+	End    int `json:"end"`
+}
+
+// RTResult содержит результаты планирования задач реального времени
+type RTResult struct {
+	SchedulerType string      `json:"scheduler"`
+	Hyperperiod   int         `json:"hyperperiod"`
+	Horizon       int         `json:"horizon"` // Длина симуляции
+	Truncated     bool        `json:"truncated,omitempty"`
+	Utilization   float64     `json:"utilization"`
+	Jobs          []RTJob     `json:"jobs"`
+	Misses        int         `json:"misses"`
+	MaxLateness   int         `json:"max_lateness"`
+	Timeline      []RTSegment `json:"timeline"`
+}
+
+// RTResponse — результат анализа времени отклика для одной задачи
+type RTResponse struct {
+	TaskID      int  `json:"task_id"`
+	Response    int  `json:"response"` // Время отклика в худшем случае
+	Deadline    int  `json:"deadline"`
+	Schedulable bool `json:"schedulable"`
+}
+
+// RTAnalysis содержит результаты тестов планируемости
+type RTAnalysis struct {
+	Utilization float64      `json:"utilization"`
+	Density     float64      `json:"density"`  // Сумма C/min(D, T)
+	RMBound     float64      `json:"rm_bound"` // Граница Лю и Лейланда n(2^(1/n) - 1)
+	RMBoundOK   bool         `json:"rm_bound_ok"`
+	RMOK        bool         `json:"rm_ok"` // По анализу времени отклика
+	EDFOK       bool         `json:"edf_ok"`
+	Responses   []RTResponse `json:"responses"`
+}
+
+// rtWCET возвращает время выполнения задачи в худшем случае
+func rtWCET(task Task) int {
+	if task.WCET > 0 {
+		return task.WCET
+	}
+	return task.Duration
+}
+
+// rtDeadline возвращает относительный крайний срок задачи
+func rtDeadline(task Task) int {
+	if task.Deadline > 0 {
+		return task.Deadline
+	}
+	return task.Period
+}
+
+// periodicTasks отбрасывает задачи без периода: для них не определены ни
+// активации, ни загрузка
+func periodicTasks(tasks []Task) []Task {
+	var periodic []Task
+	for _, task := range tasks {
+		if task.Period > 0 {
+			periodic = append(periodic, task)
+		}
+	}
+	return periodic
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// hyperperiod возвращает НОК периодов задач
+func hyperperiod(tasks []Task) int {
+	h := 1
+	for _, task := range tasks {
+		h = h / gcd(h, task.Period) * task.Period
+		if h > rtMaxHorizon {
+			return h
+		}
+	}
+	return h
+}
+
+// rmUtilizationBound возвращает границу Лю и Лейланда для n задач
+func rmUtilizationBound(n int) float64 {
+	if n == 0 {
+		return 1
+	}
+	return float64(n) * (math.Pow(2, 1/float64(n)) - 1)
+}
+
+// rmOrder возвращает индексы задач в порядке приоритетов RM (меньший период — выше)
+func rmOrder(tasks []Task) []int {
+	order := make([]int, len(tasks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ta, tb := tasks[order[a]], tasks[order[b]]
+		if ta.Period != tb.Period {
+			return ta.Period < tb.Period
+		}
+		return ta.ID < tb.ID
+	})
+	return order
+}
+
+// analyzeSchedulability выполняет тест по границе Лю и Лейланда, анализ
+// времени отклика для RM и тест плотности для EDF
+func analyzeSchedulability(tasks []Task) RTAnalysis {
+	tasks = periodicTasks(tasks)
+	var analysis RTAnalysis
+	for _, task := range tasks {
+		c := float64(rtWCET(task))
+		analysis.Utilization += c / float64(task.Period)
+		analysis.Density += c / float64(min(rtDeadline(task), task.Period))
+	}
+	analysis.RMBound = rmUtilizationBound(len(tasks))
+	analysis.RMBoundOK = analysis.Utilization <= analysis.RMBound
+	// При D >= T условие U <= 1 необходимое и достаточное, иначе тест
+	// плотности только достаточный
+	analysis.EDFOK = analysis.Density <= 1
+
+	// Анализ времени отклика: R = C_i + сумма по более приоритетным ceil(R/T_j)*C_j
+	analysis.RMOK = true
+	order := rmOrder(tasks)
+	for k, idx := range order {
+		task := tasks[idx]
+		deadline := rtDeadline(task)
+		response := rtWCET(task)
+		for {
+			next := rtWCET(task)
+			for _, hp := range order[:k] {
+				next += int(math.Ceil(float64(response)/float64(tasks[hp].Period))) * rtWCET(tasks[hp])
+			}
+			if next == response || next > deadline {
+				response = next
+				break
+			}
+			response = next
+		}
+		ok := response <= deadline
+		analysis.RMOK = analysis.RMOK && ok
+		analysis.Responses = append(analysis.Responses, RTResponse{
+			TaskID:      task.ID,
+			Response:    response,
+			Deadline:    deadline,
+			Schedulable: ok,
+		})
+	}
+	return analysis
+}
+
+// scheduleEDF реализует вытесняющее планирование Earliest Deadline First
+func scheduleEDF(tasks []Task, seed int64) RTResult {
+	return scheduleRealTime(tasks, "EDF", seed, func(a, b *RTJob) bool {
+		if a.Deadline != b.Deadline {
+			return a.Deadline < b.Deadline
+		}
+		if a.Release != b.Release {
+			return a.Release < b.Release
+		}
+		return a.TaskID < b.TaskID
+	})
+}
+
+// scheduleRM реализует вытесняющее планирование Rate Monotonic
+func scheduleRM(tasks []Task, seed int64) RTResult {
+	period := make(map[int]int)
+	for _, task := range tasks {
+		period[task.ID] = task.Period
+	}
+	return scheduleRealTime(tasks, "RM", seed, func(a, b *RTJob) bool {
+		if period[a.TaskID] != period[b.TaskID] {
+			return period[a.TaskID] < period[b.TaskID]
+		}
+		if a.TaskID != b.TaskID {
+			return a.TaskID < b.TaskID
+		}
+		return a.Release < b.Release
+	})
+}
+
+// scheduleRealTime моделирует выполнение периодических и спорадических задач
+// на интервале из гиперпериода после последнего смещения (Arrival).
+// Спорадические задачи активируются с интервалом не меньше Period; дополнительная
+// задержка выбирается генератором с заданным seed. Задача, нарушившая крайний
+// срок, не снимается и продолжает выполняться.
+func scheduleRealTime(tasks []Task, name string, seed int64, higher func(a, b *RTJob) bool) RTResult {
+	tasks = periodicTasks(tasks)
+	result := RTResult{SchedulerType: name, Hyperperiod: hyperperiod(tasks)}
+
+	maxOffset := 0
+	for _, task := range tasks {
+		maxOffset = max(maxOffset, task.Arrival)
+		result.Utilization += float64(rtWCET(task)) / float64(task.Period)
+	}
+	result.Horizon = maxOffset + result.Hyperperiod
+	if result.Horizon > rtMaxHorizon {
+		result.Horizon = rtMaxHorizon
+		result.Truncated = true
+	}
+
+	// Заранее вычисляем все активации на интервале симуляции
+	rng := rand.New(rand.NewSource(seed))
+	var jobs []*RTJob
+	remaining := make(map[*RTJob]int)
+	for _, task := range tasks {
+		for release := task.Arrival; release < result.Horizon; {
+			job := &RTJob{
+				TaskID:   task.ID,
+				Release:  release,
+				Deadline: release + rtDeadline(task),
+				Start:    -1,
+				Finish:   -1,
+			}
+			jobs = append(jobs, job)
+			remaining[job] = rtWCET(task)
+
+			release += task.Period
+			if task.Sporadic {
+				release += rng.Intn(task.Period)
+			}
+		}
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].Release < jobs[j].Release })
+
+	var ready []*RTJob
+	next := 0
+	for t := 0; t < result.Horizon; t++ {
+		for next < len(jobs) && jobs[next].Release <= t {
+			ready = append(ready, jobs[next])
+			next++
+		}
+
+		// Выбираем активацию с наивысшим приоритетом
+		current := -1
+		for i, job := range ready {
+			if current == -1 || higher(job, ready[current]) {
+				current = i
+			}
+		}
+
+		taskID := 0
+		if current >= 0 {
+			job := ready[current]
+			taskID = job.TaskID
+			if job.Start == -1 {
+				job.Start = t
+			}
+			remaining[job]--
+			if remaining[job] == 0 {
+				job.Finish = t + 1
+				ready = append(ready[:current], ready[current+1:]...)
+			}
+		}
+
+		if n := len(result.Timeline); n > 0 && result.Timeline[n-1].TaskID == taskID {
+			result.Timeline[n-1].End = t + 1
+		} else {
+			result.Timeline = append(result.Timeline, RTSegment{TaskID: taskID, Start: t, End: t + 1})
+		}
+	}
+
+	result.MaxLateness = math.MinInt32
+	for _, job := range jobs {
+		switch {
+		case job.Finish >= 0:
+			job.Lateness = job.Finish - job.Deadline
+			job.Missed = job.Lateness > 0
+		case job.Deadline <= result.Horizon:
+			// Не завершилась к крайнему сроку внутри интервала симуляции
+			job.Lateness = result.Horizon - job.Deadline
+			job.Missed = true
+		default:
+			continue
+		}
+		if job.Missed {
+			result.Misses++
+		}
+		result.MaxLateness = max(result.MaxLateness, job.Lateness)
+		result.Jobs = append(result.Jobs, *job)
+	}
+	if len(result.Jobs) == 0 {
+		result.MaxLateness = 0
+	}
+	return result
+}
+
+// printRTResult выводит результаты планирования реального времени
+func printRTResult(result RTResult) {
+	if err := out.RealTime(result); err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка вывода: %v\n", err)
+	}
+}
+
+// printRTAnalysis выводит результаты тестов планируемости
+func printRTAnalysis(analysis RTAnalysis) {
+	if err := out.Schedulability(analysis); err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка вывода: %v\n", err)
+	}
+}
+
+// analyzeRealTime сравнивает EDF и RM на наборах периодических задач
+func analyzeRealTime() {
+	// Набор, проходящий границу Лю и Лейланда
+	light := []Task{
+		{ID: 1, WCET: 1, Period: 4},
+		{ID: 2, WCET: 1, Period: 5},
+		{ID: 3, WCET: 2, Period: 10},
+	}
+
+	// U ≈ 0.97: EDF укладывается в сроки, RM — нет
+	heavy := []Task{
+		{ID: 1, WCET: 2, Period: 5},
+		{ID: 2, WCET: 4, Period: 7},
+	}
+
+	// Спорадическая задача с ограниченным крайним сроком
+	mixed := []Task{
+		{ID: 1, WCET: 1, Period: 4, Deadline: 3},
+		{ID: 2, WCET: 2, Period: 6},
+		{ID: 3, WCET: 3, Period: 12, Sporadic: true},
+	}
+
+	sets := []struct {
+		id, name string
+		tasks    []Task
+	}{
+		{"realtime-light", "Легкая нагрузка", light},
+		{"realtime-heavy", "Тяжелая нагрузка (U ≈ 0.97)", heavy},
+		{"realtime-mixed", "Смешанная нагрузка со спорадической задачей", mixed},
+	}
+
+	for _, set := range sets {
+		fmt.Fprintf(logOut, "\n--- %s ---\n", set.name)
+		beginSection(set.id, "8. Реальное время: "+set.name)
+		printRTAnalysis(analyzeSchedulability(set.tasks))
+		fmt.Fprintln(logOut)
+		printRTResult(scheduleEDF(set.tasks, 1))
+		fmt.Fprintln(logOut)
+		printRTResult(scheduleRM(set.tasks, 1))
+	}
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// policyConfig — настройки запуска политики при переборе. Каждая политика
+// использует свои поля, а параметры перебора меняют их через sweepParam.Set.
+type policyConfig struct {
+	Quantum  int      // rr: квант
+	Interval int      // aging: интервал старения
+	MP       MPConfig // sqms, mqms
+}
+
+// sweepParam — числовой параметр политики
+type sweepParam struct {
+	Min, Max int                                // Допустимые значения (Max 0 — без верхней границы)
+	Set      func(cfg *policyConfig, value int) // Записывает значение в настройки
+}
+
+// sweepPolicy описывает политику планирования и ее числовые параметры
+type sweepPolicy struct {
+	Param  string                // Параметр, который перебирается, если имя не указано
+	Config policyConfig          // Настройки по умолчанию
+	Params map[string]sweepParam // Параметры, которые можно перебирать
+	Run    func(tasks []Task, cfg policyConfig) SchedulerResult
+}
+
+// sweepPolicies содержит политики, параметры которых можно перебирать
+var sweepPolicies = map[string]sweepPolicy{
+	"rr": {
+		Param: "quantum",
+		Params: map[string]sweepParam{
+			"quantum": {Min: 1, Set: func(cfg *policyConfig, v int) { cfg.Quantum = v }},
+		},
+		Run: func(tasks []Task, cfg policyConfig) SchedulerResult { return scheduleRR(tasks, cfg.Quantum) },
+	},
+	"aging": {
+		Param: "interval",
+		Params: map[string]sweepParam{
+			"interval": {Min: 0, Set: func(cfg *policyConfig, v int) { cfg.Interval = v }}, // 0 — без старения
+		},
+		Run: func(tasks []Task, cfg policyConfig) SchedulerResult {
+			return schedulePriorityAging(tasks, cfg.Interval)
+		},
+	},
+	"sqms": {
+		Param:  "cpus",
+		Config: policyConfig{MP: MPConfig{CPUs: 2, Mode: MPSingleQueue, TimeQuantum: 10, Affinity: true, CacheWarmup: 4}},
+		Params: mpSweepParams(map[string]sweepParam{
+			"affinity": {Min: 0, Max: 1, Set: func(cfg *policyConfig, v int) { cfg.MP.Affinity = v != 0 }},
+		}),
+		Run: runMultiprocessorPolicy,
+	},
+	"mqms": {
+		Param:  "cpus",
+		Config: policyConfig{MP: MPConfig{CPUs: 2, Mode: MPMultiQueue, TimeQuantum: 10, Stealing: true, CacheWarmup: 4}},
+		Params: mpSweepParams(map[string]sweepParam{
+			"stealing": {Min: 0, Max: 1, Set: func(cfg *policyConfig, v int) { cfg.MP.Stealing = v != 0 }},
+		}),
+		Run: runMultiprocessorPolicy,
+	},
+}
+
+// mpSweepParams дополняет параметры многопроцессорной политики общими для
+// SQMS и MQMS: количеством CPU, квантом и прогревом кэша
+func mpSweepParams(params map[string]sweepParam) map[string]sweepParam {
+	params["cpus"] = sweepParam{Min: 1, Set: func(cfg *policyConfig, v int) { cfg.MP.CPUs = v }}
+	params["quantum"] = sweepParam{Min: 1, Set: func(cfg *policyConfig, v int) { cfg.MP.TimeQuantum = v }}
+	params["warmup"] = sweepParam{Min: 0, Set: func(cfg *policyConfig, v int) { cfg.MP.CacheWarmup = v }}
+	return params
+}
+
+// runMultiprocessorPolicy запускает многопроцессорную политику при переборе
+func runMultiprocessorPolicy(tasks []Task, cfg policyConfig) SchedulerResult {
+	return scheduleMultiprocessor(tasks, cfg.MP).SchedulerResult
+}
+
+// sweepPolicyNames возвращает отсортированный список доступных политик
+func sweepPolicyNames() []string {
+	names := make([]string, 0, len(sweepPolicies))
+	for name := range sweepPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// paramNames возвращает отсортированный список параметров политики
+func (p sweepPolicy) paramNames() []string {
+	names := make([]string, 0, len(p.Params))
+	for name := range p.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Workload — именованный набор задач
+type Workload struct {
+	Name  string `json:"name"`
+	Tasks []Task `json:"tasks"`
+}
+
+// SweepConfig задает перебор параметра политики
+type SweepConfig struct {
+	Policy    string     // Название политики из sweepPolicies
+	Param     string     // Перебираемый параметр ("" — параметр политики по умолчанию)
+	Values    []int      // Значения параметра
+	Workloads []Workload // Рабочие нагрузки
+	Workers   int        // Размер пула воркеров (0 = количество CPU)
+	Metric    string     // Минимизируемая метрика: response, turnaround, waiting
+}
+
+// SweepCell — результат одной комбинации нагрузки и значения параметра
+type SweepCell struct {
+	Workload string          `json:"workload"`
+	Value    int             `json:"value"`
+	Result   SchedulerResult `json:"result"`
+}
+
+// SweepBest — лучшее значение параметра для нагрузки
+type SweepBest struct {
+	Workload string  `json:"workload"` // "все" для среднего по всем нагрузкам
+	Value    int     `json:"value"`
+	Score    float64 `json:"score"`
+}
+
+// SweepResult содержит сетку результатов и лучшие конфигурации
+type SweepResult struct {
+	Policy    string        `json:"policy"`
+	Param     string        `json:"param"`
+	Metric    string        `json:"metric"`
+	Values    []int         `json:"values"`
+	Workloads []string      `json:"workloads"`
+	Grid      [][]SweepCell `json:"grid"` // Grid[нагрузка][значение]
+	Best      []SweepBest   `json:"best"`
+}
+
+// sweepAllWorkloads — имя строки со средним по всем нагрузкам
+const sweepAllWorkloads = "все"
+
+// metricValue возвращает значение метрики для результата
+func metricValue(result SchedulerResult, metric string) (float64, error) {
+	switch metric {
+	case "response":
+		return result.AvgResponse, nil
+	case "turnaround":
+		return result.AvgTurnaround, nil
+	case "waiting":
+		return result.AvgWaiting, nil
+	}
+	return 0, fmt.Errorf("неизвестная метрика: %s", metric)
+}
+
+// parseSweepSpec разбирает перебор вида "политика.параметр=диапазон" или
+// только диапазон; тогда политика — policy, а параметр — ее параметр по умолчанию
+func parseSweepSpec(s, policy string) (SweepConfig, error) {
+	cfg := SweepConfig{Policy: policy}
+	spec, values, ok := strings.Cut(s, "=")
+	if !ok {
+		values = s
+	} else {
+		name, param, ok := strings.Cut(strings.TrimSpace(spec), ".")
+		if !ok || name == "" || param == "" {
+			return SweepConfig{}, fmt.Errorf("неверный перебор: %s (ожидается политика.параметр=диапазон)", s)
+		}
+		cfg.Policy, cfg.Param = name, param
+	}
+	var err error
+	cfg.Values, err = parseRange(values)
+	return cfg, err
+}
+
+// parseRange разбирает диапазон "от:до[:шаг]" (включительно) или список "1,5,10"
+func parseRange(s string) ([]int, error) {
+	if strings.Contains(s, ":") {
+		parts := strings.Split(s, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("неверный диапазон: %s", s)
+		}
+		bounds := make([]int, 3)
+		bounds[2] = 1
+		for i, part := range parts {
+			v, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("неверный диапазон: %s", s)
+			}
+			bounds[i] = v
+		}
+		from, to, step := bounds[0], bounds[1], bounds[2]
+		if step <= 0 || from > to {
+			return nil, fmt.Errorf("неверный диапазон: %s", s)
+		}
+		var values []int
+		for v := from; v <= to; v += step {
+			values = append(values, v)
+		}
+		return values, nil
+	}
+
+	var values []int
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("неверное значение параметра: %s", part)
+		}
+		values = append(values, v)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("пустой диапазон: %s", s)
+	}
+	return values, nil
+}
+
+// runSweep запускает все комбинации нагрузок и значений параметра в пуле воркеров.
+// Каждая комбинация пишет результат в свою ячейку сетки, поэтому результат не
+// зависит от количества воркеров и порядка их выполнения.
+func runSweep(cfg SweepConfig) (SweepResult, error) {
+	policy, ok := sweepPolicies[cfg.Policy]
+	if !ok {
+		return SweepResult{}, fmt.Errorf("неизвестная политика: %s (доступны: %s)",
+			cfg.Policy, strings.Join(sweepPolicyNames(), ", "))
+	}
+	if cfg.Param == "" {
+		cfg.Param = policy.Param
+	}
+	param, ok := policy.Params[cfg.Param]
+	if !ok {
+		return SweepResult{}, fmt.Errorf("%s: неизвестный параметр %s (доступны: %s)",
+			cfg.Policy, cfg.Param, strings.Join(policy.paramNames(), ", "))
+	}
+	if len(cfg.Values) == 0 || len(cfg.Workloads) == 0 {
+		return SweepResult{}, fmt.Errorf("пустой набор значений или нагрузок")
+	}
+	for _, value := range cfg.Values {
+		if value < param.Min {
+			return SweepResult{}, fmt.Errorf("%s: значение %s должно быть не меньше %d: %d",
+				cfg.Policy, cfg.Param, param.Min, value)
+		}
+		if param.Max > 0 && value > param.Max {
+			return SweepResult{}, fmt.Errorf("%s: значение %s должно быть не больше %d: %d",
+				cfg.Policy, cfg.Param, param.Max, value)
+		}
+	}
+	if cfg.Metric == "" {
+		cfg.Metric = "turnaround"
+	}
+	if _, err := metricValue(SchedulerResult{}, cfg.Metric); err != nil {
+		return SweepResult{}, err
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	result := SweepResult{
+		Policy: cfg.Policy,
+		Param:  cfg.Param,
+		Metric: cfg.Metric,
+		Values: cfg.Values,
+		Grid:   make([][]SweepCell, len(cfg.Workloads)),
+	}
+	for i, workload := range cfg.Workloads {
+		result.Workloads = append(result.Workloads, workload.Name)
+		result.Grid[i] = make([]SweepCell, len(cfg.Values))
+	}
+
+	type cellIndex struct{ workload, value int }
+	cells := make(chan cellIndex)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range cells {
+				workload := cfg.Workloads[idx.workload]
+				value := cfg.Values[idx.value]
+				// Политики могут менять порядок задач, поэтому каждая
+				// комбинация работает со своей копией
+				tasks := make([]Task, len(workload.Tasks))
+				copy(tasks, workload.Tasks)
+				// Настройки тоже копируются: воркеры меняют параметр одновременно
+				settings := policy.Config
+				param.Set(&settings, value)
+				result.Grid[idx.workload][idx.value] = SweepCell{
+					Workload: workload.Name,
+					Value:    value,
+					Result:   policy.Run(tasks, settings),
+				}
+			}
+		}()
+	}
+
+	for w := range cfg.Workloads {
+		for v := range cfg.Values {
+			cells <- cellIndex{w, v}
+		}
+	}
+	close(cells)
+	wg.Wait()
+
+	result.Best = bestConfigurations(result)
+	return result, nil
+}
+
+// bestConfigurations находит лучшее значение параметра для каждой нагрузки и в
+// среднем по всем нагрузкам. При равенстве выбирается более раннее значение.
+func bestConfigurations(result SweepResult) []SweepBest {
+	var best []SweepBest
+	totals := make([]float64, len(result.Values))
+
+	for w, row := range result.Grid {
+		b := SweepBest{Workload: result.Workloads[w], Score: math.Inf(1)}
+		for v, cell := range row {
+			score, _ := metricValue(cell.Result, result.Metric)
+			totals[v] += score
+			if score < b.Score {
+				b.Value = cell.Value
+				b.Score = score
+			}
+		}
+		best = append(best, b)
+	}
+
+	overall := SweepBest{Workload: sweepAllWorkloads, Score: math.Inf(1)}
+	for v, total := range totals {
+		score := total / float64(len(result.Grid))
+		if score < overall.Score {
+			overall.Value = result.Values[v]
+			overall.Score = score
+		}
+	}
+	return append(best, overall)
+}
+
+// loadWorkloads читает нагрузки для перебора из JSON-файла:
+//
+//	[{"name": "конвой", "tasks": [{"id": 1, "duration": 100, "arrival": 0, "priority": 2}, ...]}, ...]
+//
+// Задачам без id присваивается номер по порядку.
+func loadWorkloads(path string) ([]Workload, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var workloads []Workload
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&workloads); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(workloads) == 0 {
+		return nil, fmt.Errorf("%s: нет нагрузок", path)
+	}
+	for w := range workloads {
+		workload := &workloads[w]
+		if workload.Name == "" {
+			workload.Name = fmt.Sprintf("нагрузка %d", w+1)
+		}
+		if len(workload.Tasks) == 0 {
+			return nil, fmt.Errorf("%s: нагрузка %q без задач", path, workload.Name)
+		}
+		for i := range workload.Tasks {
+			task := &workload.Tasks[i]
+			if task.ID == 0 {
+				task.ID = i + 1
+			}
+			if task.Duration < 1 || task.Arrival < 0 || task.Priority < 0 {
+				return nil, fmt.Errorf("%s: нагрузка %q, задача %d: длительность должна быть не меньше 1, прибытие и приоритет — не меньше 0",
+					path, workload.Name, task.ID)
+			}
+		}
+	}
+	return workloads, nil
+}
+
+// defaultSweepWorkloads возвращает нагрузки, на которых по умолчанию выполняется перебор.
+// Приоритеты у задач разные, чтобы перебор старения (aging) менял результат.
+func defaultSweepWorkloads() []Workload {
+	starvation := []Task{{ID: 1, Duration: 40, Arrival: 1, Priority: 5}}
+	for i := 0; i < 30; i++ {
+		starvation = append(starvation, Task{ID: i + 2, Duration: 5, Arrival: i * 5, Priority: 1})
+	}
+	return []Workload{
+		{Name: "равные", Tasks: []Task{
+			{ID: 1, Duration: 80, Arrival: 0, Priority: 2},
+			{ID: 2, Duration: 60, Arrival: 10, Priority: 1},
+			{ID: 3, Duration: 60, Arrival: 20, Priority: 0},
+		}},
+		{Name: "конвой", Tasks: []Task{
+			{ID: 1, Duration: 100, Arrival: 0, Priority: 3},
+			{ID: 2, Duration: 10, Arrival: 5, Priority: 1},
+			{ID: 3, Duration: 20, Arrival: 10, Priority: 2},
+			{ID: 4, Duration: 5, Arrival: 15, Priority: 0},
+		}},
+		{Name: "короткие", Tasks: []Task{
+			{ID: 1, Duration: 1, Arrival: 0},
+			{ID: 2, Duration: 1, Arrival: 1},
+			{ID: 3, Duration: 1, Arrival: 2},
+		}},
+		{Name: "длинные", Tasks: []Task{
+			{ID: 1, Duration: 100, Arrival: 0, Priority: 1},
+			{ID: 2, Duration: 100, Arrival: 10, Priority: 0},
+			{ID: 3, Duration: 100, Arrival: 20, Priority: 0},
+		}},
+		{Name: "голодание", Tasks: starvation},
+	}
+}
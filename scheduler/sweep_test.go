@@ -0,0 +1,98 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestRunSweepWorkers проверяет, что сетка перебора не зависит от количества
+// воркеров: для каждой политики и каждого ее параметра
+func TestRunSweepWorkers(t *testing.T) {
+	for _, name := range sweepPolicyNames() {
+		policy := sweepPolicies[name]
+		for _, param := range policy.paramNames() {
+			t.Run(name+"."+param, func(t *testing.T) {
+				bounds := policy.Params[param]
+				var values []int
+				for v := bounds.Min; v <= bounds.Min+5 && (bounds.Max == 0 || v <= bounds.Max); v++ {
+					values = append(values, v)
+				}
+				cfg := SweepConfig{Policy: name, Param: param, Values: values, Workloads: defaultSweepWorkloads()}
+
+				cfg.Workers = 1
+				serial, err := runSweep(cfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				cfg.Workers = 8
+				parallel, err := runSweep(cfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(serial, parallel) {
+					t.Errorf("результаты с 1 и 8 воркерами различаются")
+				}
+				if serial.Param != param {
+					t.Errorf("параметр %s, ожидается %s", serial.Param, param)
+				}
+			})
+		}
+	}
+}
+
+func TestParseSweepSpec(t *testing.T) {
+	cases := []struct {
+		spec   string
+		policy string
+		param  string
+		values []int
+		err    string
+	}{
+		{spec: "1:3", policy: "rr", values: []int{1, 2, 3}},
+		{spec: "rr.quantum=1,5,10", policy: "rr", param: "quantum", values: []int{1, 5, 10}},
+		{spec: "mqms.quantum=2:10:4", policy: "mqms", param: "quantum", values: []int{2, 6, 10}},
+		{spec: "mqms=1:3", err: "неверный перебор"},
+		{spec: ".quantum=1:3", err: "неверный перебор"},
+		{spec: "rr.quantum=3:1", err: "неверный диапазон"},
+		{spec: "rr.quantum=", err: "пустой диапазон"},
+	}
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			cfg, err := parseSweepSpec(c.spec, "rr")
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("ошибка %v, ожидается %q", err, c.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Policy != c.policy || cfg.Param != c.param || !reflect.DeepEqual(cfg.Values, c.values) {
+				t.Errorf("получено %s.%s=%v, ожидается %s.%s=%v", cfg.Policy, cfg.Param, cfg.Values, c.policy, c.param, c.values)
+			}
+		})
+	}
+}
+
+func TestRunSweepErrors(t *testing.T) {
+	workloads := defaultSweepWorkloads()
+	cases := []struct {
+		cfg SweepConfig
+		err string
+	}{
+		{SweepConfig{Policy: "fifo", Values: []int{1}}, "неизвестная политика: fifo"},
+		{SweepConfig{Policy: "rr", Param: "interval", Values: []int{1}}, "rr: неизвестный параметр interval (доступны: quantum)"},
+		{SweepConfig{Policy: "rr", Values: []int{0}}, "значение quantum должно быть не меньше 1"},
+		{SweepConfig{Policy: "aging", Values: []int{-1}}, "значение interval должно быть не меньше 0"},
+		{SweepConfig{Policy: "rr", Values: []int{1}, Metric: "max"}, "неизвестная метрика: max"},
+		{SweepConfig{Policy: "rr"}, "пустой набор значений"},
+	}
+	for _, c := range cases {
+		c.cfg.Workloads = workloads
+		if _, err := runSweep(c.cfg); err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%+v: ошибка %v, ожидается %q", c.cfg, err, c.err)
+		}
+	}
+}